@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// chirpInput is a chirp about to be published, either straight from a
+// request or from a scheduled draft.
+type chirpInput struct {
+	Body        string
+	InReplyToID *uuid.UUID
+	RechirpOfID *uuid.UUID
+	QuoteOfID   *uuid.UUID
+	MediaIDs    []uuid.UUID
+	Poll        *pollParams
+	// Visibility defaults to public. ContentWarning, if set, is shown in
+	// place of the body until the reader opens the chirp.
+	Visibility     string
+	ContentWarning string
+	// ExpiresIn, in seconds, makes the chirp disappear that long after it's
+	// published.
+	ExpiresIn *int
+}
+
+// chirpInputError is a chirp that can't be published as submitted. Status
+// and Message are what the client should see.
+type chirpInputError struct {
+	Status  int
+	Message string
+	Err     error
+}
+
+func (e *chirpInputError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *chirpInputError) Unwrap() error {
+	return e.Err
+}
+
+// insertChirp validates a chirp and stores it with its entities, poll and
+// media, and notifies whoever it replies to or mentions. Pass a
+// transaction-bound q so a chirp is never left half-created.
+func (cfg *apiConfig) insertChirp(ctx context.Context, q *database.Queries, userID uuid.UUID, input chirpInput) (database.Chirp, error) {
+	if len(input.MediaIDs) > maxMediaPerChirp {
+		return database.Chirp{}, &chirpInputError{
+			Status:  http.StatusBadRequest,
+			Message: fmt.Sprintf("A chirp can have at most %d attachments", maxMediaPerChirp),
+		}
+	}
+
+	visibility, err := parseVisibility(input.Visibility)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	contentWarning, err := cfg.validateContentWarning(input.ContentWarning)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	lifetime, err := chirpLifetime(input.ExpiresIn)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+
+	cleaned := ""
+	if input.RechirpOfID != nil {
+		if input.Body != "" || input.InReplyToID != nil || input.QuoteOfID != nil || len(input.MediaIDs) > 0 || input.Poll != nil || contentWarning.Valid {
+			return database.Chirp{}, &chirpInputError{
+				Status:  http.StatusBadRequest,
+				Message: "A rechirp can't have a body, reply, quote, media, poll or content warning",
+			}
+		}
+	} else {
+		user, err := q.GetUserByID(ctx, userID)
+		if err != nil {
+			return database.Chirp{}, err
+		}
+
+		cleaned, err = cfg.validateChirp(input.Body, chirpLengthLimit(user.IsChirpyRed))
+		if err != nil {
+			return database.Chirp{}, err
+		}
+	}
+
+	pollOptions := []string{}
+	if input.Poll != nil {
+		pollOptions, err = validatePoll(*input.Poll, time.Now())
+		if err != nil {
+			return database.Chirp{}, &chirpInputError{Status: http.StatusBadRequest, Message: err.Error(), Err: err}
+		}
+		for i, option := range pollOptions {
+			pollOptions[i] = cfg.profanityFilter().Clean(option)
+		}
+	}
+
+	inReplyToID := uuid.NullUUID{}
+	rootID := uuid.NullUUID{}
+	if input.InReplyToID != nil {
+		parent, err := getOriginalChirp(ctx, q, userID, *input.InReplyToID, "the chirp you're replying to")
+		if err != nil {
+			return database.Chirp{}, err
+		}
+		if err := checkNotBlocked(ctx, q, parent.UserID, userID); err != nil {
+			return database.Chirp{}, err
+		}
+
+		inReplyToID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+		rootID = parent.RootID
+		if !rootID.Valid {
+			rootID = uuid.NullUUID{UUID: parent.ID, Valid: true}
+		}
+	}
+
+	rechirpOfID := uuid.NullUUID{}
+	if input.RechirpOfID != nil {
+		original, err := getOriginalChirp(ctx, q, userID, *input.RechirpOfID, "the chirp to rechirp")
+		if err != nil {
+			return database.Chirp{}, err
+		}
+		if err := checkNotBlocked(ctx, q, original.UserID, userID); err != nil {
+			return database.Chirp{}, err
+		}
+		if !canRechirp(original.Visibility) {
+			return database.Chirp{}, &chirpInputError{Status: http.StatusForbidden, Message: "Only public chirps can be rechirped"}
+		}
+		rechirpOfID = uuid.NullUUID{UUID: original.ID, Valid: true}
+	}
+
+	quoteOfID := uuid.NullUUID{}
+	if input.QuoteOfID != nil {
+		quoted, err := getOriginalChirp(ctx, q, userID, *input.QuoteOfID, "the chirp to quote")
+		if err != nil {
+			return database.Chirp{}, err
+		}
+		if err := checkNotBlocked(ctx, q, quoted.UserID, userID); err != nil {
+			return database.Chirp{}, err
+		}
+		quoteOfID = uuid.NullUUID{UUID: quoted.ID, Valid: true}
+	}
+
+	chirp, err := q.CreateChirp(ctx, database.CreateChirpParams{
+		Body:           cleaned,
+		UserID:         userID,
+		InReplyToID:    inReplyToID,
+		RootID:         rootID,
+		RechirpOfID:    rechirpOfID,
+		QuoteOfID:      quoteOfID,
+		Visibility:     visibility,
+		ContentWarning: contentWarning,
+		ExpiresAt:      chirpExpiresAt(time.Now(), lifetime),
+	})
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return database.Chirp{}, &chirpInputError{Status: http.StatusConflict, Message: "You already rechirped this chirp", Err: err}
+		}
+		return database.Chirp{}, err
+	}
+
+	if err := saveChirpEntities(ctx, q, chirp); err != nil {
+		return database.Chirp{}, err
+	}
+
+	events, err := chirpNotifications(ctx, q, chirp)
+	if err != nil {
+		return database.Chirp{}, err
+	}
+	if err := recordNotifications(ctx, q, events...); err != nil {
+		return database.Chirp{}, err
+	}
+
+	if input.Poll != nil {
+		if err := createPoll(ctx, q, chirp.ID, pollOptions, input.Poll.ClosesAt); err != nil {
+			return database.Chirp{}, err
+		}
+	}
+
+	if len(input.MediaIDs) > 0 {
+		attached, err := q.AttachMedia(ctx, database.AttachMediaParams{
+			ChirpID:  chirp.ID,
+			MediaIds: input.MediaIDs,
+			UserID:   userID,
+		})
+		if err != nil {
+			return database.Chirp{}, err
+		}
+		// Each upload can be attached once, and only by whoever uploaded it.
+		if attached != int64(len(input.MediaIDs)) {
+			return database.Chirp{}, &chirpInputError{Status: http.StatusBadRequest, Message: "Invalid media_ids"}
+		}
+	}
+
+	return chirp, nil
+}
+
+// getOriginalChirp looks up a chirp being replied to, rechirped or quoted.
+// A rechirp only stands in for the chirp it shares, so it resolves to that.
+// what names the chirp in the error a client sees if it's missing or hidden
+// from userID.
+func getOriginalChirp(ctx context.Context, q *database.Queries, userID, chirpID uuid.UUID, what string) (database.Chirp, error) {
+	chirp, err := q.GetChirp(ctx, database.GetChirpParams{ID: chirpID, ViewerID: userID})
+	if err == nil && chirp.RechirpOfID.Valid {
+		chirp, err = q.GetChirp(ctx, database.GetChirpParams{ID: chirp.RechirpOfID.UUID, ViewerID: userID})
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return database.Chirp{}, &chirpInputError{Status: http.StatusNotFound, Message: "Couldn't find " + what, Err: err}
+	}
+	return chirp, err
+}
+
+// checkNotBlocked refuses to let userID reply to, rechirp or quote a chirp
+// by someone who has blocked them.
+func checkNotBlocked(ctx context.Context, q *database.Queries, authorID, userID uuid.UUID) error {
+	blocked, err := q.IsBlocked(ctx, database.IsBlockedParams{
+		BlockerID: authorID,
+		BlockedID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if blocked {
+		return &chirpInputError{Status: http.StatusForbidden, Message: "You can't interact with this user's chirps"}
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+const (
+	minChirpLifetime = time.Minute
+	maxChirpLifetime = 7 * 24 * time.Hour
+
+	chirpReaperInterval = time.Minute
+	// maxPurgedPerBatch bounds how many chirps, and how many media blobs,
+	// one reaper transaction holds on to.
+	maxPurgedPerBatch = 500
+)
+
+// chirpLifetime checks a requested expires_in, in seconds. nil means the
+// chirp never expires.
+func chirpLifetime(expiresIn *int) (time.Duration, error) {
+	if expiresIn == nil {
+		return 0, nil
+	}
+
+	lifetime := time.Duration(*expiresIn) * time.Second
+	if lifetime < minChirpLifetime || lifetime > maxChirpLifetime {
+		return 0, &chirpInputError{
+			Status: http.StatusBadRequest,
+			Message: fmt.Sprintf(
+				"expires_in must be between %d and %d seconds",
+				int(minChirpLifetime.Seconds()),
+				int(maxChirpLifetime.Seconds()),
+			),
+		}
+	}
+	return lifetime, nil
+}
+
+// chirpExpiresAt is when a chirp published at now with the given lifetime
+// disappears, or NULL if it doesn't.
+func chirpExpiresAt(now time.Time, lifetime time.Duration) sql.NullTime {
+	if lifetime == 0 {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: now.UTC().Add(lifetime), Valid: true}
+}
+
+// purgeExpiredChirps deletes chirps past their expiry for good. Reads stop
+// returning them as soon as they expire, so this only reclaims the space.
+// Chirps with replies become tombstones instead, as they do when deleted,
+// so their threads stay intact.
+func (cfg *apiConfig) purgeExpiredChirps(ctx context.Context) {
+	ticker := time.NewTicker(chirpReaperInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for {
+				purged, err := cfg.purgeExpiredChirpBatch(ctx)
+				if err != nil {
+					log.Printf("Couldn't purge expired chirps: %v", err)
+					break
+				}
+				cfg.chirpsPurged.Add(purged)
+				if purged < maxPurgedPerBatch {
+					break
+				}
+			}
+		}
+	}
+}
+
+func (cfg *apiConfig) purgeExpiredChirpBatch(ctx context.Context) (int64, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	chirpIDs, err := qtx.ListExpiredChirpIDs(ctx, maxPurgedPerBatch)
+	if err != nil {
+		return 0, err
+	}
+	if len(chirpIDs) == 0 {
+		return 0, nil
+	}
+
+	blobKeys := []string{}
+	for _, chirpID := range chirpIDs {
+		keys, err := deleteChirpMedia(ctx, qtx, chirpID)
+		if err != nil {
+			return 0, err
+		}
+		blobKeys = append(blobKeys, keys...)
+	}
+
+	if err := qtx.DeleteEntitiesOfChirps(ctx, chirpIDs); err != nil {
+		return 0, err
+	}
+	tombstoned, err := qtx.TombstoneChirpsWithReplies(ctx, chirpIDs)
+	if err != nil {
+		return 0, err
+	}
+	purged, err := qtx.PurgeChirps(ctx, chirpIDs)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	cfg.deleteBlobs(ctx, blobKeys...)
+	return tombstoned + purged, nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChirpLifetime(t *testing.T) {
+	seconds := func(n int) *int { return &n }
+
+	cases := []struct {
+		name      string
+		expiresIn *int
+		want      time.Duration
+		wantErr   bool
+	}{
+		{name: "never", expiresIn: nil, want: 0},
+		{name: "one hour", expiresIn: seconds(3600), want: time.Hour},
+		{name: "shortest", expiresIn: seconds(60), want: minChirpLifetime},
+		{name: "longest", expiresIn: seconds(7 * 24 * 3600), want: maxChirpLifetime},
+		{name: "too short", expiresIn: seconds(59), wantErr: true},
+		{name: "zero", expiresIn: seconds(0), wantErr: true},
+		{name: "negative", expiresIn: seconds(-60), wantErr: true},
+		{name: "too long", expiresIn: seconds(7*24*3600 + 1), wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := chirpLifetime(c.expiresIn)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("chirpLifetime() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("chirpLifetime() unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Errorf("chirpLifetime() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestChirpExpiresAt(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60))
+
+	if got := chirpExpiresAt(now, 0); got.Valid {
+		t.Errorf("chirpExpiresAt(now, 0) = %v, want NULL", got)
+	}
+
+	got := chirpExpiresAt(now, time.Hour)
+	want := time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)
+	if !got.Valid || !got.Time.Equal(want) || got.Time.Location() != time.UTC {
+		t.Errorf("chirpExpiresAt(now, 1h) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	chirpMaxLength    = 140
+	chirpRedMaxLength = 280
+	// chirpURLWeight is what every link counts for, however long it is, so
+	// users aren't punished for long URLs.
+	chirpURLWeight = 23
+)
+
+// chirpLengthError reports a chirp body that is over the author's limit.
+type chirpLengthError struct {
+	Length int
+	Limit  int
+}
+
+func (e *chirpLengthError) Error() string {
+	return fmt.Sprintf("Chirp is too long: %d characters, limit is %d", e.Length, e.Limit)
+}
+
+func chirpLengthLimit(isChirpyRed bool) int {
+	if isChirpyRed {
+		return chirpRedMaxLength
+	}
+	return chirpMaxLength
+}
+
+// chirpLength counts a body the way users perceive it: one per grapheme
+// cluster, so an emoji with modifiers is a single character, and a fixed
+// weight per URL.
+func chirpLength(body string) int {
+	runes := []rune(body)
+	length := 0
+	offset := 0
+	for _, entity := range extractEntities(body) {
+		if entity.Type != entityURL {
+			continue
+		}
+		length += uniseg.GraphemeClusterCount(string(runes[offset:entity.Start])) + chirpURLWeight
+		offset = entity.End
+	}
+	return length + uniseg.GraphemeClusterCount(string(runes[offset:]))
+}
+
+// validateChirp normalizes a chirp body to NFC, checks it against limit and
+// returns it with restricted words censored.
+func (cfg *apiConfig) validateChirp(body string, limit int) (string, error) {
+	normalized := norm.NFC.String(body)
+	if length := chirpLength(normalized); length > limit {
+		return "", &chirpLengthError{Length: length, Limit: limit}
+	}
+
+	cleaned := cfg.profanityFilter().Clean(normalized)
+	return cleaned, nil
+}
+
+// respondWithChirpError answers a chirp that couldn't be saved, including the
+// computed length and limit when the body was too long.
+func respondWithChirpError(w http.ResponseWriter, err error) {
+	type lengthErrorResponse struct {
+		Error  string `json:"error"`
+		Length int    `json:"length"`
+		Limit  int    `json:"limit"`
+	}
+
+	var lengthErr *chirpLengthError
+	if errors.As(err, &lengthErr) {
+		respondWithJSON(w, http.StatusBadRequest, lengthErrorResponse{
+			Error:  "Chirp is too long",
+			Length: lengthErr.Length,
+			Limit:  lengthErr.Limit,
+		})
+		return
+	}
+
+	var inputErr *chirpInputError
+	if errors.As(err, &inputErr) {
+		respondWithError(w, inputErr.Status, inputErr.Message, err)
+		return
+	}
+
+	respondWithError(w, http.StatusInternalServerError, "Couldn't save the chirp", err)
+}
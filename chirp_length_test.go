@@ -0,0 +1,57 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestChirpLength(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want int
+	}{
+		{name: "ascii", body: "hello world", want: 11},
+		{name: "emoji", body: strings.Repeat("😀", 50), want: 50},
+		{name: "emoji with skin tone", body: "👍🏽", want: 1},
+		{name: "family emoji", body: "👨‍👩‍👧", want: 1},
+		{name: "combining accent", body: "e\u0301", want: 1},
+		{name: "url", body: "see https://example.com/a/very/long/path/that/goes/on/and/on", want: 4 + chirpURLWeight},
+		{name: "url with trailing period", body: "https://example.com.", want: chirpURLWeight + 1},
+		{name: "two urls", body: "http://a.io and http://b.io", want: 2*chirpURLWeight + 5},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := chirpLength(c.body); got != c.want {
+				t.Errorf("chirpLength(%q) = %d, want %d", c.body, got, c.want)
+			}
+		})
+	}
+}
+
+func TestValidateChirp(t *testing.T) {
+	cfg := &apiConfig{}
+
+	cleaned, err := cfg.validateChirp("cafe\u0301 kerfuffle", chirpMaxLength)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "caf\u00e9 ****"; cleaned != want {
+		t.Errorf("got %q, want %q", cleaned, want)
+	}
+
+	_, err = cfg.validateChirp(strings.Repeat("a", chirpMaxLength+1), chirpMaxLength)
+	var lengthErr *chirpLengthError
+	if !errors.As(err, &lengthErr) {
+		t.Fatalf("expected a length error, got %v", err)
+	}
+	if lengthErr.Length != chirpMaxLength+1 || lengthErr.Limit != chirpMaxLength {
+		t.Errorf("got length %d limit %d", lengthErr.Length, lengthErr.Limit)
+	}
+
+	if _, err := cfg.validateChirp(strings.Repeat("a", chirpMaxLength+1), chirpRedMaxLength); err != nil {
+		t.Errorf("red limit: unexpected error: %v", err)
+	}
+}
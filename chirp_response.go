@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+type Chirp struct {
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+	Body           string            `json:"body"`
+	ContentWarning string            `json:"content_warning,omitempty"`
+	Visibility     string            `json:"visibility"`
+	ExpiresAt      *time.Time        `json:"expires_at,omitempty"`
+	ID             uuid.UUID         `json:"id"`
+	UserID         uuid.UUID         `json:"user_id"`
+	InReplyToID    *uuid.UUID        `json:"in_reply_to_id,omitempty"`
+	ConversationID uuid.UUID         `json:"conversation_id"`
+	Deleted        bool              `json:"deleted,omitempty"`
+	LikeCount      int32             `json:"like_count"`
+	ReactionCounts map[string]int    `json:"reaction_counts"`
+	LikedByMe      bool              `json:"liked_by_me"`
+	BookmarkedByMe bool              `json:"bookmarked_by_me"`
+	Pinned         bool              `json:"pinned"`
+	RechirpOf      *Chirp            `json:"rechirp_of,omitempty"`
+	QuotedChirp    *Chirp            `json:"quoted_chirp,omitempty"`
+	Entities       []Entity          `json:"entities"`
+	Media          []MediaAttachment `json:"media"`
+	Poll           *Poll             `json:"poll,omitempty"`
+}
+
+func chirpFromDB(chirp database.Chirp) Chirp {
+	response := Chirp{
+		ID:             chirp.ID,
+		CreatedAt:      chirp.CreatedAt,
+		UpdatedAt:      chirp.UpdatedAt,
+		Body:           chirp.Body,
+		ContentWarning: chirp.ContentWarning.String,
+		Visibility:     chirp.Visibility,
+		UserID:         chirp.UserID,
+		ConversationID: chirp.ID,
+		Deleted:        chirp.DeletedAt.Valid,
+		LikeCount:      chirp.LikeCount,
+		ReactionCounts: map[string]int{},
+		Entities:       []Entity{},
+		Media:          []MediaAttachment{},
+	}
+	if chirp.InReplyToID.Valid {
+		response.InReplyToID = &chirp.InReplyToID.UUID
+	}
+	if chirp.RootID.Valid {
+		response.ConversationID = chirp.RootID.UUID
+	}
+	if chirp.ExpiresAt.Valid {
+		response.ExpiresAt = &chirp.ExpiresAt.Time
+	}
+	if response.Deleted {
+		response.ContentWarning = ""
+	}
+	json.Unmarshal(chirp.ReactionCounts, &response.ReactionCounts)
+	return response
+}
+
+// chirpResponses converts a page of chirps for the given viewer, batching the
+// per-viewer lookups so list endpoints cost a fixed number of queries.
+// viewerID is uuid.Nil for anonymous requests.
+func (cfg *apiConfig) chirpResponses(ctx context.Context, viewerID uuid.UUID, chirps []database.Chirp) ([]Chirp, error) {
+	referencedIDs := []uuid.UUID{}
+	for _, chirp := range chirps {
+		if chirp.RechirpOfID.Valid {
+			referencedIDs = append(referencedIDs, chirp.RechirpOfID.UUID)
+		}
+		if chirp.QuoteOfID.Valid {
+			referencedIDs = append(referencedIDs, chirp.QuoteOfID.UUID)
+		}
+	}
+
+	// Embedded chirps are one level deep: a quoted chirp's own quote is only
+	// referenced by ID. A quoted chirp the viewer can't see is left out.
+	referenced := []database.Chirp{}
+	if len(referencedIDs) > 0 {
+		var err error
+		referenced, err = cfg.db.ListChirpsByIDs(ctx, database.ListChirpsByIDsParams{
+			Ids:      referencedIDs,
+			ViewerID: viewerID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	chirpIDs := make([]uuid.UUID, 0, len(chirps)+len(referenced))
+	for _, chirp := range chirps {
+		chirpIDs = append(chirpIDs, chirp.ID)
+	}
+	for _, chirp := range referenced {
+		chirpIDs = append(chirpIDs, chirp.ID)
+	}
+
+	liked := map[uuid.UUID]bool{}
+	if viewerID != uuid.Nil && len(chirpIDs) > 0 {
+		likedIDs, err := cfg.db.ListLikedChirpIDs(ctx, database.ListLikedChirpIDsParams{
+			UserID:   viewerID,
+			ChirpIds: chirpIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range likedIDs {
+			liked[id] = true
+		}
+	}
+
+	bookmarked := map[uuid.UUID]bool{}
+	if viewerID != uuid.Nil && len(chirpIDs) > 0 {
+		bookmarkedIDs, err := cfg.db.ListBookmarkedChirpIDs(ctx, database.ListBookmarkedChirpIDsParams{
+			UserID:   viewerID,
+			ChirpIds: chirpIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range bookmarkedIDs {
+			bookmarked[id] = true
+		}
+	}
+
+	pinned := map[uuid.UUID]bool{}
+	if len(chirpIDs) > 0 {
+		pinnedIDs, err := cfg.db.ListPinnedChirpIDs(ctx, chirpIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range pinnedIDs {
+			pinned[id] = true
+		}
+	}
+
+	entities := map[uuid.UUID][]Entity{}
+	if len(chirpIDs) > 0 {
+		rows, err := cfg.db.ListChirpEntities(ctx, chirpIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			entity := Entity{
+				Type:  row.Kind,
+				Value: row.Value,
+				Start: int(row.StartOffset),
+				End:   int(row.EndOffset),
+			}
+			if row.MentionedUserID.Valid {
+				entity.UserID = &row.MentionedUserID.UUID
+			}
+			entities[row.ChirpID] = append(entities[row.ChirpID], entity)
+		}
+	}
+
+	attachments := map[uuid.UUID][]MediaAttachment{}
+	if len(chirpIDs) > 0 {
+		rows, err := cfg.db.ListChirpMedia(ctx, chirpIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			attachments[row.ChirpID.UUID] = append(attachments[row.ChirpID.UUID], mediaAttachmentFromDB(row))
+		}
+	}
+
+	polls, err := cfg.chirpPolls(ctx, viewerID, chirpIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	embedded := map[uuid.UUID]Chirp{}
+	for _, chirp := range referenced {
+		c := chirpFromDB(chirp)
+		c.LikedByMe = liked[chirp.ID]
+		c.BookmarkedByMe = bookmarked[chirp.ID]
+		c.Pinned = pinned[chirp.ID]
+		if e, ok := entities[chirp.ID]; ok && !c.Deleted {
+			c.Entities = e
+		}
+		if m, ok := attachments[chirp.ID]; ok {
+			c.Media = m
+		}
+		if p, ok := polls[chirp.ID]; ok && !c.Deleted {
+			c.Poll = &p
+		}
+		embedded[chirp.ID] = c
+	}
+
+	response := make([]Chirp, 0, len(chirps))
+	for _, chirp := range chirps {
+		c := chirpFromDB(chirp)
+		c.LikedByMe = liked[chirp.ID]
+		c.BookmarkedByMe = bookmarked[chirp.ID]
+		c.Pinned = pinned[chirp.ID]
+		if e, ok := entities[chirp.ID]; ok && !c.Deleted {
+			c.Entities = e
+		}
+		if m, ok := attachments[chirp.ID]; ok {
+			c.Media = m
+		}
+		if p, ok := polls[chirp.ID]; ok && !c.Deleted {
+			c.Poll = &p
+		}
+		if original, ok := embedded[chirp.RechirpOfID.UUID]; ok && chirp.RechirpOfID.Valid {
+			c.RechirpOf = &original
+		}
+		if quoted, ok := embedded[chirp.QuoteOfID.UUID]; ok && chirp.QuoteOfID.Valid {
+			c.QuotedChirp = &quoted
+		}
+		response = append(response, c)
+	}
+	return response, nil
+}
+
+// withoutHiddenRechirps drops the rechirps in response whose original the
+// viewer can't see, such as one by an author they blocked, since there's
+// nothing left of them to show. response must line up with chirps, as
+// chirpResponses returns it.
+func withoutHiddenRechirps(chirps []database.Chirp, response []Chirp) []Chirp {
+	filtered := make([]Chirp, 0, len(response))
+	for i, c := range response {
+		if chirps[i].RechirpOfID.Valid && c.RechirpOf == nil {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered
+}
+
+// chirpPolls loads the polls attached to a set of chirps as the viewer sees
+// them.
+func (cfg *apiConfig) chirpPolls(ctx context.Context, viewerID uuid.UUID, chirpIDs []uuid.UUID) (map[uuid.UUID]Poll, error) {
+	if len(chirpIDs) == 0 {
+		return map[uuid.UUID]Poll{}, nil
+	}
+
+	polls, err := cfg.db.ListPolls(ctx, chirpIDs)
+	if err != nil || len(polls) == 0 {
+		return map[uuid.UUID]Poll{}, err
+	}
+
+	pollIDs := make([]uuid.UUID, len(polls))
+	for i, poll := range polls {
+		pollIDs[i] = poll.ChirpID
+	}
+
+	optionRows, err := cfg.db.ListPollOptions(ctx, pollIDs)
+	if err != nil {
+		return nil, err
+	}
+	options := map[uuid.UUID][]database.PollOption{}
+	for _, option := range optionRows {
+		options[option.ChirpID] = append(options[option.ChirpID], option)
+	}
+
+	votes := map[uuid.UUID]int32{}
+	if viewerID != uuid.Nil {
+		voteRows, err := cfg.db.ListPollVotesByUser(ctx, database.ListPollVotesByUserParams{
+			UserID:   viewerID,
+			ChirpIds: pollIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, vote := range voteRows {
+			votes[vote.ChirpID] = vote.Position
+		}
+	}
+
+	now := time.Now().UTC()
+	response := make(map[uuid.UUID]Poll, len(polls))
+	for _, poll := range polls {
+		var votedOption *int32
+		if position, ok := votes[poll.ChirpID]; ok {
+			votedOption = &position
+		}
+		response[poll.ChirpID] = pollFromDB(poll, options[poll.ChirpID], votedOption, now)
+	}
+	return response, nil
+}
+
+// respondWithChirpFeed writes one page of a newest-first chirp list that was
+// fetched with a lookahead row.
+func (cfg *apiConfig) respondWithChirpFeed(w http.ResponseWriter, req *http.Request, viewerID uuid.UUID, chirps []database.Chirp, page pageParams) {
+	chirps, next, _ := paginate(chirps, page, chirpCursor)
+
+	response, err := cfg.chirpResponses(req.Context(), viewerID, chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't build the chirp response", err)
+		return
+	}
+	response = withoutHiddenRechirps(chirps, response)
+
+	response, err = cfg.withoutMutedKeywords(req.Context(), viewerID, response)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't apply muted keywords", err)
+		return
+	}
+
+	setPaginationHeaders(w, req, next, "")
+	respondWithJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestWithoutHiddenRechirps(t *testing.T) {
+	original := uuid.New()
+	plain := database.Chirp{ID: uuid.New()}
+	shown := database.Chirp{ID: uuid.New(), RechirpOfID: uuid.NullUUID{UUID: original, Valid: true}}
+	hidden := database.Chirp{ID: uuid.New(), RechirpOfID: uuid.NullUUID{UUID: uuid.New(), Valid: true}}
+
+	chirps := []database.Chirp{plain, shown, hidden}
+	response := []Chirp{
+		{ID: plain.ID},
+		{ID: shown.ID, RechirpOf: &Chirp{ID: original}},
+		{ID: hidden.ID},
+	}
+
+	got := withoutHiddenRechirps(chirps, response)
+	if len(got) != 2 || got[0].ID != plain.ID || got[1].ID != shown.ID {
+		t.Errorf("got %v, want the plain chirp and the rechirp with its original", got)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+const (
+	chirpCreatedChannel = "chirp_created"
+	streamListenerPing  = 90 * time.Second
+	// maxStreamBacklog is how many new chirps a stream subscriber can fall
+	// behind by before it's told to catch up from the database instead.
+	maxStreamBacklog = 1000
+)
+
+// chirpStream fans new chirp ids out to every open /api/stream connection
+// on this instance. Ids arrive from Postgres, so chirps created through any
+// instance reach all of them.
+type chirpStream struct {
+	mu          sync.Mutex
+	subscribers map[*streamSubscriber]struct{}
+}
+
+func newChirpStream() *chirpStream {
+	return &chirpStream{subscribers: map[*streamSubscriber]struct{}{}}
+}
+
+// streamSubscriber collects the ids published since it last looked. wake
+// has room for one signal, so publishing never blocks on a slow client.
+type streamSubscriber struct {
+	mu      sync.Mutex
+	pending []uuid.UUID
+	resync  bool
+	wake    chan struct{}
+}
+
+func (s *chirpStream) subscribe() *streamSubscriber {
+	sub := &streamSubscriber{wake: make(chan struct{}, 1)}
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+	return sub
+}
+
+func (s *chirpStream) unsubscribe(sub *streamSubscriber) {
+	s.mu.Lock()
+	delete(s.subscribers, sub)
+	s.mu.Unlock()
+}
+
+func (s *chirpStream) publish(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		sub.add(id)
+	}
+}
+
+// resyncAll tells every subscriber that ids may have been lost, so they
+// should catch up from the database rather than trust what they were sent.
+func (s *chirpStream) resyncAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subscribers {
+		sub.markResync()
+	}
+}
+
+func (sub *streamSubscriber) add(id uuid.UUID) {
+	sub.mu.Lock()
+	if !sub.resync {
+		if len(sub.pending) < maxStreamBacklog {
+			sub.pending = append(sub.pending, id)
+		} else {
+			sub.pending = nil
+			sub.resync = true
+		}
+	}
+	sub.mu.Unlock()
+	sub.signal()
+}
+
+func (sub *streamSubscriber) markResync() {
+	sub.mu.Lock()
+	sub.pending = nil
+	sub.resync = true
+	sub.mu.Unlock()
+	sub.signal()
+}
+
+func (sub *streamSubscriber) signal() {
+	select {
+	case sub.wake <- struct{}{}:
+	default:
+	}
+}
+
+// take returns the ids published since the last call, or resync if the
+// subscriber has to catch up from the database instead.
+func (sub *streamSubscriber) take() (ids []uuid.UUID, resync bool) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	ids, resync = sub.pending, sub.resync
+	sub.pending, sub.resync = nil, false
+	return ids, resync
+}
+
+// listenForChirps relays chirp_created notifications into cfg.chirpStream
+// until ctx is done.
+func (cfg *apiConfig) listenForChirps(ctx context.Context, dbURL string) {
+	listener := pq.NewListener(dbURL, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Chirp stream listener: %v", err)
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(chirpCreatedChannel); err != nil {
+		log.Printf("Couldn't listen for new chirps: %v", err)
+		return
+	}
+
+	ping := time.NewTicker(streamListenerPing)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notification := <-listener.Notify:
+			// pq sends nil after reconnecting; anything published while the
+			// connection was down is gone.
+			if notification == nil {
+				cfg.chirpStream.resyncAll()
+				continue
+			}
+			id, err := uuid.Parse(notification.Extra)
+			if err != nil {
+				log.Printf("Ignoring malformed chirp notification %q: %v", notification.Extra, err)
+				continue
+			}
+			cfg.chirpStream.publish(id)
+		case <-ping.C:
+			go listener.Ping()
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestChirpStreamPublish(t *testing.T) {
+	stream := newChirpStream()
+	a := stream.subscribe()
+	b := stream.subscribe()
+
+	first, second := uuid.New(), uuid.New()
+	stream.publish(first)
+	stream.unsubscribe(b)
+	stream.publish(second)
+
+	select {
+	case <-a.wake:
+	default:
+		t.Fatal("expected the subscriber to be woken")
+	}
+
+	ids, resync := a.take()
+	if resync || !slices.Equal(ids, []uuid.UUID{first, second}) {
+		t.Errorf("a got %v (resync %v), want both ids", ids, resync)
+	}
+	ids, resync = b.take()
+	if resync || !slices.Equal(ids, []uuid.UUID{first}) {
+		t.Errorf("b got %v (resync %v), want only the id before it left", ids, resync)
+	}
+
+	if ids, resync := a.take(); resync || len(ids) != 0 {
+		t.Errorf("second take got %v (resync %v), want nothing", ids, resync)
+	}
+}
+
+func TestChirpStreamBacklog(t *testing.T) {
+	stream := newChirpStream()
+	sub := stream.subscribe()
+
+	for range maxStreamBacklog + 1 {
+		stream.publish(uuid.New())
+	}
+
+	ids, resync := sub.take()
+	if !resync || len(ids) != 0 {
+		t.Errorf("got %d ids (resync %v), want a resync instead", len(ids), resync)
+	}
+
+	stream.publish(uuid.New())
+	if ids, resync := sub.take(); resync || len(ids) != 1 {
+		t.Errorf("after resync got %d ids (resync %v), want 1", len(ids), resync)
+	}
+}
+
+func TestChirpStreamResyncAll(t *testing.T) {
+	stream := newChirpStream()
+	sub := stream.subscribe()
+	stream.publish(uuid.New())
+	stream.resyncAll()
+
+	if ids, resync := sub.take(); !resync || len(ids) != 0 {
+		t.Errorf("got %d ids (resync %v), want a resync", len(ids), resync)
+	}
+}
+
+func TestCursorAfter(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	low := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	high := uuid.MustParse("ffffffff-0000-0000-0000-000000000000")
+	c := pageCursor{CreatedAt: now, ID: low}
+
+	cases := []struct {
+		name      string
+		createdAt time.Time
+		id        uuid.UUID
+		want      bool
+	}{
+		{name: "later", createdAt: now.Add(time.Millisecond), id: low, want: true},
+		{name: "earlier", createdAt: now.Add(-time.Millisecond), id: high, want: false},
+		{name: "same time, higher id", createdAt: now, id: high, want: true},
+		{name: "same chirp", createdAt: now, id: low, want: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := cursorAfter(tc.createdAt, tc.id, c); got != tc.want {
+				t.Errorf("cursorAfter = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if !cursorAfter(now, low, pageCursor{CreatedAt: now}) {
+		t.Error("a cursor without an id should sort before chirps at the same time")
+	}
+}
+
+func TestStreamPositionLateCommit(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	pos := newStreamPosition(pageCursor{CreatedAt: start})
+
+	// late started its transaction first but committed after newer.
+	late := database.Chirp{ID: uuid.New(), CreatedAt: start.Add(time.Second)}
+	newer := database.Chirp{ID: uuid.New(), CreatedAt: start.Add(2 * time.Second)}
+
+	pos.markSent(pos.unsent([]database.Chirp{newer}))
+	if got := pos.unsent([]database.Chirp{late}); len(got) != 1 {
+		t.Fatalf("unsent after newer = %v, want the late chirp", got)
+	}
+	pos.markSent([]database.Chirp{late})
+
+	if pos.last.ID != newer.ID {
+		t.Errorf("last = %v, want the newer chirp", pos.last)
+	}
+	if got := pos.unsent([]database.Chirp{late, newer}); len(got) != 0 {
+		t.Errorf("unsent = %v, want nothing sent twice", got)
+	}
+	if from := pos.catchUpFrom(); !from.CreatedAt.Before(late.CreatedAt) {
+		t.Errorf("catch-up starts at %v, after the late chirp", from.CreatedAt)
+	}
+}
+
+func TestStreamPositionForgetsOldChirps(t *testing.T) {
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	pos := newStreamPosition(pageCursor{CreatedAt: start})
+
+	old := database.Chirp{ID: uuid.New(), CreatedAt: start.Add(time.Second)}
+	pos.markSent([]database.Chirp{old})
+	pos.markSent([]database.Chirp{{ID: uuid.New(), CreatedAt: old.CreatedAt.Add(2 * streamReorderWindow)}})
+
+	if _, ok := pos.sent[old.ID]; ok {
+		t.Error("expected a chirp outside the reorder window to be forgotten")
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Who can see a chirp. Unlisted chirps are public to anyone who has the link
+// but stay out of the global feed, hashtag pages and search. The same rules
+// are enforced in SQL by chirp_visible_to.
+const (
+	visibilityPublic    = "public"
+	visibilityFollowers = "followers"
+	visibilityMentioned = "mentioned"
+	visibilityUnlisted  = "unlisted"
+
+	contentWarningMaxLength = 100
+)
+
+// parseVisibility checks a requested visibility. Chirps are public unless
+// the author says otherwise.
+func parseVisibility(visibility string) (string, error) {
+	switch visibility {
+	case "":
+		return visibilityPublic, nil
+	case visibilityPublic, visibilityFollowers, visibilityMentioned, visibilityUnlisted:
+		return visibility, nil
+	}
+	return "", &chirpInputError{
+		Status:  http.StatusBadRequest,
+		Message: "Visibility must be one of public, followers, mentioned or unlisted",
+	}
+}
+
+// canRechirp reports whether a chirp may be shared further. Rechirping a
+// followers-only or mentioned-only chirp would show it to people the author
+// didn't pick.
+func canRechirp(visibility string) bool {
+	return visibility == visibilityPublic || visibility == visibilityUnlisted
+}
+
+// validateContentWarning normalizes the spoiler text shown in place of a
+// chirp's body and censors it like the body. An empty warning is NULL.
+func (cfg *apiConfig) validateContentWarning(warning string) (sql.NullString, error) {
+	if warning == "" {
+		return sql.NullString{}, nil
+	}
+
+	normalized := norm.NFC.String(warning)
+	if uniseg.GraphemeClusterCount(normalized) > contentWarningMaxLength {
+		return sql.NullString{}, &chirpInputError{
+			Status:  http.StatusBadRequest,
+			Message: fmt.Sprintf("A content warning can be at most %d characters", contentWarningMaxLength),
+		}
+	}
+
+	return sql.NullString{String: cfg.profanityFilter().Clean(normalized), Valid: true}, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseVisibility(t *testing.T) {
+	cases := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "", want: visibilityPublic},
+		{input: "public", want: visibilityPublic},
+		{input: "followers", want: visibilityFollowers},
+		{input: "mentioned", want: visibilityMentioned},
+		{input: "unlisted", want: visibilityUnlisted},
+		{input: "Public", wantErr: true},
+		{input: "private", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.input, func(t *testing.T) {
+			got, err := parseVisibility(c.input)
+			if c.wantErr {
+				var inputErr *chirpInputError
+				if !errors.As(err, &inputErr) {
+					t.Fatalf("parseVisibility(%q) error = %v, want a chirpInputError", c.input, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseVisibility(%q) unexpected error: %v", c.input, err)
+			}
+			if got != c.want {
+				t.Errorf("parseVisibility(%q) = %q, want %q", c.input, got, c.want)
+			}
+		})
+	}
+}
+
+func TestCanRechirp(t *testing.T) {
+	cases := map[string]bool{
+		visibilityPublic:    true,
+		visibilityUnlisted:  true,
+		visibilityFollowers: false,
+		visibilityMentioned: false,
+	}
+
+	for visibility, want := range cases {
+		if got := canRechirp(visibility); got != want {
+			t.Errorf("canRechirp(%q) = %v, want %v", visibility, got, want)
+		}
+	}
+}
+
+func TestValidateContentWarning(t *testing.T) {
+	cfg := &apiConfig{}
+
+	cases := []struct {
+		name      string
+		warning   string
+		want      string
+		wantValid bool
+		wantErr   bool
+	}{
+		{name: "empty", warning: ""},
+		{name: "plain", warning: "spoilers for the finale", want: "spoilers for the finale", wantValid: true},
+		{name: "censored", warning: "kerfuffle ahead", want: "**** ahead", wantValid: true},
+		{name: "at limit", warning: strings.Repeat("👍🏽", contentWarningMaxLength), want: strings.Repeat("👍🏽", contentWarningMaxLength), wantValid: true},
+		{name: "too long", warning: strings.Repeat("a", contentWarningMaxLength+1), wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := cfg.validateContentWarning(c.warning)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("validateContentWarning(%q) succeeded, want an error", c.warning)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateContentWarning(%q) unexpected error: %v", c.warning, err)
+			}
+			if got.Valid != c.wantValid || got.String != c.want {
+				t.Errorf("validateContentWarning(%q) = %+v, want %q (valid %v)", c.warning, got, c.want, c.wantValid)
+			}
+		})
+	}
+}
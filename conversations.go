@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// maxConversationMembers includes whoever started the conversation.
+	maxConversationMembers     = 50
+	maxConversationTitleLength = 100
+	maxMessageLength           = 1000
+)
+
+type Conversation struct {
+	ID              uuid.UUID   `json:"id"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+	IsGroup         bool        `json:"is_group"`
+	Title           string      `json:"title,omitempty"`
+	FilterProfanity bool        `json:"filter_profanity"`
+	MemberIDs       []uuid.UUID `json:"member_ids"`
+	UnreadCount     int64       `json:"unread_count"`
+	LastMessage     *Message    `json:"last_message,omitempty"`
+}
+
+type Message struct {
+	ID             uuid.UUID  `json:"id"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ConversationID uuid.UUID  `json:"conversation_id"`
+	SenderID       *uuid.UUID `json:"sender_id"`
+	Body           string     `json:"body"`
+}
+
+func messageFromDB(message database.Message) Message {
+	response := Message{
+		ID:             message.ID,
+		CreatedAt:      message.CreatedAt,
+		ConversationID: message.ConversationID,
+		Body:           message.Body,
+	}
+	if message.SenderID.Valid {
+		response.SenderID = &message.SenderID.UUID
+	}
+	return response
+}
+
+// conversationMemberIDs works out who else a new conversation is with. The
+// caller is always a member, so listing them is allowed but changes nothing.
+func conversationMemberIDs(userID uuid.UUID, memberIDs []uuid.UUID) ([]uuid.UUID, error) {
+	others := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{userID: true}
+	for _, id := range memberIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		others = append(others, id)
+	}
+
+	if len(others) == 0 {
+		return nil, errors.New("A conversation needs at least one other member")
+	}
+	if len(others)+1 > maxConversationMembers {
+		return nil, fmt.Errorf("A conversation can have at most %d members", maxConversationMembers)
+	}
+	return others, nil
+}
+
+// conversationTitle checks the optional name of a group conversation.
+// Direct conversations are named after whoever is on the other side.
+func conversationTitle(title string, isGroup bool) (sql.NullString, error) {
+	title = strings.TrimSpace(norm.NFC.String(title))
+	if title == "" {
+		return sql.NullString{}, nil
+	}
+	if !isGroup {
+		return sql.NullString{}, errors.New("Only group conversations can have a title")
+	}
+	if uniseg.GraphemeClusterCount(title) > maxConversationTitleLength {
+		return sql.NullString{}, fmt.Errorf("A title can be at most %d characters", maxConversationTitleLength)
+	}
+	return sql.NullString{String: title, Valid: true}, nil
+}
+
+// validateMessage normalizes a message body to NFC and checks its length.
+// Messages are only censored in conversations that opted in to the filter.
+func (cfg *apiConfig) validateMessage(body string, filterProfanity bool) (string, error) {
+	normalized := norm.NFC.String(body)
+	if strings.TrimSpace(normalized) == "" {
+		return "", errors.New("A message can't be empty")
+	}
+	if uniseg.GraphemeClusterCount(normalized) > maxMessageLength {
+		return "", fmt.Errorf("A message can be at most %d characters", maxMessageLength)
+	}
+
+	if filterProfanity {
+		return cfg.profanityFilter().Clean(normalized), nil
+	}
+	return normalized, nil
+}
+
+// conversationResponses converts conversations for one of their members,
+// batching the member, unread and last message lookups.
+func (cfg *apiConfig) conversationResponses(ctx context.Context, userID uuid.UUID, conversations []database.Conversation) ([]Conversation, error) {
+	response := make([]Conversation, 0, len(conversations))
+	if len(conversations) == 0 {
+		return response, nil
+	}
+
+	conversationIDs := make([]uuid.UUID, len(conversations))
+	for i, conversation := range conversations {
+		conversationIDs[i] = conversation.ID
+	}
+
+	members := map[uuid.UUID][]uuid.UUID{}
+	memberRows, err := cfg.db.ListConversationMembers(ctx, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range memberRows {
+		members[row.ConversationID] = append(members[row.ConversationID], row.UserID)
+	}
+
+	unread := map[uuid.UUID]int64{}
+	unreadRows, err := cfg.db.ListUnreadCounts(ctx, database.ListUnreadCountsParams{
+		UserID:          userID,
+		ConversationIds: conversationIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range unreadRows {
+		unread[row.ConversationID] = row.UnreadCount
+	}
+
+	latest := map[uuid.UUID]Message{}
+	latestRows, err := cfg.db.ListLatestMessages(ctx, conversationIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range latestRows {
+		latest[row.ConversationID] = messageFromDB(row)
+	}
+
+	for _, conversation := range conversations {
+		c := Conversation{
+			ID:              conversation.ID,
+			CreatedAt:       conversation.CreatedAt,
+			UpdatedAt:       conversation.UpdatedAt,
+			IsGroup:         conversation.IsGroup,
+			Title:           conversation.Title.String,
+			FilterProfanity: conversation.FilterProfanity,
+			MemberIDs:       []uuid.UUID{},
+			UnreadCount:     unread[conversation.ID],
+		}
+		if m, ok := members[conversation.ID]; ok {
+			c.MemberIDs = m
+		}
+		if message, ok := latest[conversation.ID]; ok {
+			c.LastMessage = &message
+		}
+		response = append(response, c)
+	}
+	return response, nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestConversationMemberIDs(t *testing.T) {
+	self := uuid.New()
+	alice := uuid.New()
+	bob := uuid.New()
+
+	tooMany := []uuid.UUID{}
+	for range maxConversationMembers {
+		tooMany = append(tooMany, uuid.New())
+	}
+
+	cases := []struct {
+		name    string
+		members []uuid.UUID
+		want    []uuid.UUID
+		wantErr bool
+	}{
+		{name: "direct", members: []uuid.UUID{alice}, want: []uuid.UUID{alice}},
+		{name: "group keeps order", members: []uuid.UUID{bob, alice}, want: []uuid.UUID{bob, alice}},
+		{name: "drops self and duplicates", members: []uuid.UUID{self, alice, alice, bob}, want: []uuid.UUID{alice, bob}},
+		{name: "only self", members: []uuid.UUID{self}, wantErr: true},
+		{name: "empty", members: nil, wantErr: true},
+		{name: "at limit", members: tooMany[:maxConversationMembers-1], want: tooMany[:maxConversationMembers-1]},
+		{name: "too many", members: tooMany, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := conversationMemberIDs(self, c.members)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("conversationMemberIDs() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("conversationMemberIDs() unexpected error: %v", err)
+			}
+			if !slices.Equal(got, c.want) {
+				t.Errorf("conversationMemberIDs() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestConversationTitle(t *testing.T) {
+	cases := []struct {
+		name    string
+		title   string
+		isGroup bool
+		want    string
+		wantErr bool
+	}{
+		{name: "no title", title: "", isGroup: false, want: ""},
+		{name: "group title", title: "  Book club ", isGroup: true, want: "Book club"},
+		{name: "direct title", title: "Book club", isGroup: false, wantErr: true},
+		{name: "too long", title: strings.Repeat("a", maxConversationTitleLength+1), isGroup: true, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := conversationTitle(c.title, c.isGroup)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("conversationTitle(%q) = %v, want an error", c.title, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("conversationTitle(%q) unexpected error: %v", c.title, err)
+			}
+			if got.String != c.want || got.Valid != (c.want != "") {
+				t.Errorf("conversationTitle(%q) = %+v, want %q", c.title, got, c.want)
+			}
+		})
+	}
+}
+
+func TestValidateMessage(t *testing.T) {
+	cfg := &apiConfig{}
+
+	cases := []struct {
+		name            string
+		body            string
+		filterProfanity bool
+		want            string
+		wantErr         bool
+	}{
+		{name: "plain", body: "see you at 8", want: "see you at 8"},
+		{name: "unfiltered", body: "what a kerfuffle", want: "what a kerfuffle"},
+		{name: "filtered", body: "what a kerfuffle", filterProfanity: true, want: "what a ****"},
+		{name: "nfc", body: "cafe\u0301", want: "caf\u00e9"},
+		{name: "blank", body: " \n ", wantErr: true},
+		{name: "too long", body: strings.Repeat("a", maxMessageLength+1), wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := cfg.validateMessage(c.body, c.filterProfanity)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("validateMessage(%q) = %q, want an error", c.body, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("validateMessage(%q) unexpected error: %v", c.body, err)
+			}
+			if got != c.want {
+				t.Errorf("validateMessage(%q) = %q, want %q", c.body, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	draftStatusDraft     = "draft"
+	draftStatusScheduled = "scheduled"
+	draftStatusPublished = "published"
+	draftStatusFailed    = "failed"
+
+	draftSchedulerInterval = 15 * time.Second
+	// maxDraftsPerTick keeps one scheduler run from holding on to a backlog
+	// that other instances could be sharing.
+	maxDraftsPerTick = 100
+)
+
+type Draft struct {
+	ID             uuid.UUID   `json:"id"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+	Body           string      `json:"body"`
+	ContentWarning string      `json:"content_warning,omitempty"`
+	ExpiresIn      *int        `json:"expires_in,omitempty"`
+	Visibility     string      `json:"visibility"`
+	InReplyToID    *uuid.UUID  `json:"in_reply_to_id,omitempty"`
+	QuoteOfID      *uuid.UUID  `json:"quote_of_id,omitempty"`
+	MediaIDs       []uuid.UUID `json:"media_ids"`
+	PublishAt      *time.Time  `json:"publish_at,omitempty"`
+	Status         string      `json:"status"`
+	Error          string      `json:"error,omitempty"`
+	ChirpID        *uuid.UUID  `json:"chirp_id,omitempty"`
+	PublishedAt    *time.Time  `json:"published_at,omitempty"`
+}
+
+func draftFromDB(draft database.Draft) Draft {
+	response := Draft{
+		ID:             draft.ID,
+		CreatedAt:      draft.CreatedAt,
+		UpdatedAt:      draft.UpdatedAt,
+		Body:           draft.Body,
+		ContentWarning: draft.ContentWarning.String,
+		Visibility:     draft.Visibility,
+		MediaIDs:       append([]uuid.UUID{}, draft.MediaIds...),
+		Status:         draft.Status,
+		Error:          draft.Error.String,
+	}
+	if draft.InReplyToID.Valid {
+		response.InReplyToID = &draft.InReplyToID.UUID
+	}
+	if draft.QuoteOfID.Valid {
+		response.QuoteOfID = &draft.QuoteOfID.UUID
+	}
+	if draft.PublishAt.Valid {
+		response.PublishAt = &draft.PublishAt.Time
+	}
+	if draft.ExpiresInSeconds.Valid {
+		expiresIn := int(draft.ExpiresInSeconds.Int32)
+		response.ExpiresIn = &expiresIn
+	}
+	if draft.ChirpID.Valid {
+		response.ChirpID = &draft.ChirpID.UUID
+	}
+	if draft.PublishedAt.Valid {
+		response.PublishedAt = &draft.PublishedAt.Time
+	}
+	return response
+}
+
+type draftParams struct {
+	Body           string      `json:"body"`
+	InReplyToID    *uuid.UUID  `json:"in_reply_to_id"`
+	QuoteOfID      *uuid.UUID  `json:"quote_of_id"`
+	MediaIDs       []uuid.UUID `json:"media_ids"`
+	PublishAt      *time.Time  `json:"publish_at"`
+	Visibility     string      `json:"visibility"`
+	ContentWarning string      `json:"content_warning"`
+	ExpiresIn      *int        `json:"expires_in"`
+}
+
+// status is the state a draft is saved in: drafts with a publish time are
+// picked up by the scheduler.
+func (p draftParams) status() string {
+	if p.PublishAt != nil {
+		return draftStatusScheduled
+	}
+	return draftStatusDraft
+}
+
+// visibility is stored already defaulted, so a draft reads back the way it
+// will be published. validateDraft has checked it.
+func (p draftParams) visibility() string {
+	visibility, _ := parseVisibility(p.Visibility)
+	return visibility
+}
+
+// contentWarning is stored as written; it's censored when the draft is
+// published, along with the body.
+func (p draftParams) contentWarning() sql.NullString {
+	return sql.NullString{String: p.ContentWarning, Valid: p.ContentWarning != ""}
+}
+
+// expiresIn stays a duration rather than a deadline, so a scheduled chirp
+// lives as long as asked from when it's published.
+func (p draftParams) expiresIn() sql.NullInt32 {
+	if p.ExpiresIn == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(*p.ExpiresIn), Valid: true}
+}
+
+func (p draftParams) publishAt() sql.NullTime {
+	if p.PublishAt == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: p.PublishAt.UTC(), Valid: true}
+}
+
+// validateDraft catches what it can before a draft is saved. The draft goes
+// through insertChirp again when it's published, since the chirps it refers
+// to may be gone by then.
+func (cfg *apiConfig) validateDraft(ctx context.Context, userID uuid.UUID, params draftParams) error {
+	if len(params.MediaIDs) > maxMediaPerChirp {
+		return &chirpInputError{
+			Status:  http.StatusBadRequest,
+			Message: fmt.Sprintf("A chirp can have at most %d attachments", maxMediaPerChirp),
+		}
+	}
+
+	if _, err := parseVisibility(params.Visibility); err != nil {
+		return err
+	}
+	if _, err := cfg.validateContentWarning(params.ContentWarning); err != nil {
+		return err
+	}
+	if _, err := chirpLifetime(params.ExpiresIn); err != nil {
+		return err
+	}
+
+	user, err := cfg.db.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	_, err = cfg.validateChirp(params.Body, chirpLengthLimit(user.IsChirpyRed))
+	return err
+}
+
+func (cfg *apiConfig) createDraft(ctx context.Context, userID uuid.UUID, params draftParams) (database.Draft, error) {
+	if err := cfg.validateDraft(ctx, userID, params); err != nil {
+		return database.Draft{}, err
+	}
+
+	return cfg.db.CreateDraft(ctx, database.CreateDraftParams{
+		UserID:           userID,
+		Body:             params.Body,
+		InReplyToID:      nullUUID(params.InReplyToID),
+		QuoteOfID:        nullUUID(params.QuoteOfID),
+		MediaIds:         append([]uuid.UUID{}, params.MediaIDs...),
+		PublishAt:        params.publishAt(),
+		Status:           params.status(),
+		Visibility:       params.visibility(),
+		ContentWarning:   params.contentWarning(),
+		ExpiresInSeconds: params.expiresIn(),
+	})
+}
+
+func nullUUID(id *uuid.UUID) uuid.NullUUID {
+	if id == nil {
+		return uuid.NullUUID{}
+	}
+	return uuid.NullUUID{UUID: *id, Valid: true}
+}
+
+func draftInput(draft database.Draft) chirpInput {
+	input := chirpInput{
+		Body:           draft.Body,
+		MediaIDs:       draft.MediaIds,
+		Visibility:     draft.Visibility,
+		ContentWarning: draft.ContentWarning.String,
+	}
+	if draft.InReplyToID.Valid {
+		input.InReplyToID = &draft.InReplyToID.UUID
+	}
+	if draft.QuoteOfID.Valid {
+		input.QuoteOfID = &draft.QuoteOfID.UUID
+	}
+	if draft.ExpiresInSeconds.Valid {
+		expiresIn := int(draft.ExpiresInSeconds.Int32)
+		input.ExpiresIn = &expiresIn
+	}
+	return input
+}
+
+// publishScheduledDrafts periodically publishes drafts whose time has come.
+func (cfg *apiConfig) publishScheduledDrafts(ctx context.Context) {
+	ticker := time.NewTicker(draftSchedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for range maxDraftsPerTick {
+				due, err := cfg.publishNextDraft(ctx)
+				if err != nil {
+					log.Printf("Couldn't publish scheduled draft: %v", err)
+					break
+				}
+				if !due {
+					break
+				}
+			}
+		}
+	}
+}
+
+// publishNextDraft publishes the most overdue scheduled draft and reports
+// whether there was one. A draft that can't be published is marked failed
+// with the reason instead. The draft stays locked until it's published or
+// marked failed, and other instances skip locked rows, so every draft is
+// published at most once however many servers are running.
+func (cfg *apiConfig) publishNextDraft(ctx context.Context) (bool, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	draft, err := qtx.ClaimDueDraft(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// A failed insert aborts the transaction; the savepoint lets us undo it
+	// and still record the failure against the locked draft.
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT publish_draft"); err != nil {
+		return false, err
+	}
+
+	chirp, err := cfg.insertChirp(ctx, qtx, draft.UserID, draftInput(draft))
+	if err != nil {
+		message := "Couldn't publish the chirp"
+		var inputErr *chirpInputError
+		var lengthErr *chirpLengthError
+		switch {
+		case errors.As(err, &inputErr):
+			message = inputErr.Message
+		case errors.As(err, &lengthErr):
+			message = "Chirp is too long"
+		default:
+			log.Printf("Couldn't publish draft %s: %v", draft.ID, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT publish_draft"); err != nil {
+			return false, err
+		}
+		if err := qtx.MarkDraftFailed(ctx, database.MarkDraftFailedParams{
+			Error: message,
+			ID:    draft.ID,
+		}); err != nil {
+			return false, err
+		}
+		return true, tx.Commit()
+	}
+
+	if err := qtx.MarkDraftPublished(ctx, database.MarkDraftPublishedParams{
+		ChirpID: chirp.ID,
+		ID:      draft.ID,
+	}); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
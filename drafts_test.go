@@ -0,0 +1,53 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestDraftParamsStatus(t *testing.T) {
+	publishAt := time.Date(2024, 1, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	draft := draftParams{}
+	if got := draft.status(); got != draftStatusDraft {
+		t.Errorf("status() = %q, want %q", got, draftStatusDraft)
+	}
+	if draft.publishAt().Valid {
+		t.Error("publishAt() is set for an unscheduled draft")
+	}
+
+	scheduled := draftParams{PublishAt: &publishAt}
+	if got := scheduled.status(); got != draftStatusScheduled {
+		t.Errorf("status() = %q, want %q", got, draftStatusScheduled)
+	}
+	// The column has no time zone, so the time must be stored as UTC.
+	if got := scheduled.publishAt(); !got.Valid || got.Time.Location() != time.UTC || !got.Time.Equal(publishAt) {
+		t.Errorf("publishAt() = %v, want %v in UTC", got, publishAt)
+	}
+}
+
+func TestDraftInput(t *testing.T) {
+	replyTo := uuid.New()
+	mediaID := uuid.New()
+	draft := database.Draft{
+		Body:        "hello",
+		InReplyToID: uuid.NullUUID{UUID: replyTo, Valid: true},
+		MediaIds:    []uuid.UUID{mediaID},
+		PublishAt:   sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	input := draftInput(draft)
+	if input.Body != "hello" || input.InReplyToID == nil || *input.InReplyToID != replyTo {
+		t.Errorf("got %+v", input)
+	}
+	if input.QuoteOfID != nil || input.RechirpOfID != nil || input.Poll != nil {
+		t.Errorf("unexpected references in %+v", input)
+	}
+	if len(input.MediaIDs) != 1 || input.MediaIDs[0] != mediaID {
+		t.Errorf("MediaIDs = %v, want [%v]", input.MediaIDs, mediaID)
+	}
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	entityHashtag = "hashtag"
+	entityMention = "mention"
+	entityURL     = "url"
+)
+
+// Entity is a span of a chirp body that clients render specially. Start and
+// End are rune offsets into the body, End exclusive.
+type Entity struct {
+	Type   string     `json:"type"`
+	Value  string     `json:"value"`
+	Start  int        `json:"start"`
+	End    int        `json:"end"`
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+}
+
+var entityPattern = regexp.MustCompile(`(https?://\S+)|#([\p{L}\p{N}_]+)|@([A-Za-z0-9_]+)`)
+
+// extractEntities finds URLs, hashtags and mention candidates in a chirp
+// body. Mentions are returned by handle; callers drop the ones that don't
+// belong to a user.
+func extractEntities(body string) []Entity {
+	entities := []Entity{}
+	for _, match := range entityPattern.FindAllStringSubmatchIndex(body, -1) {
+		start, end := match[0], match[1]
+
+		entity := Entity{}
+		switch {
+		case match[2] >= 0:
+			// Sentence punctuation right after a link is almost never part
+			// of it.
+			trimmed := strings.TrimRight(body[start:end], ".,!?:;'\")]")
+			end = start + len(trimmed)
+			entity = Entity{Type: entityURL, Value: trimmed}
+		case match[4] >= 0:
+			if !startsWord(body, start) {
+				continue
+			}
+			entity = Entity{Type: entityHashtag, Value: body[match[4]:match[5]]}
+		case match[6] >= 0:
+			if !startsWord(body, start) {
+				continue
+			}
+			entity = Entity{Type: entityMention, Value: body[match[6]:match[7]]}
+		}
+
+		entity.Start = utf8.RuneCountInString(body[:start])
+		entity.End = entity.Start + utf8.RuneCountInString(body[start:end])
+		entities = append(entities, entity)
+	}
+	return entities
+}
+
+// startsWord reports whether a sigil at byte offset i begins a new word, so
+// "me@example.com" or "issue#12" aren't mistaken for entities.
+func startsWord(body string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(body[:i])
+	return !unicode.IsLetter(prev) && !unicode.IsDigit(prev) && prev != '_'
+}
+
+// saveChirpEntities replaces the stored entities of a chirp with the ones in
+// its current body. Pass a transaction-bound q so they change with the body.
+func saveChirpEntities(ctx context.Context, q *database.Queries, chirp database.Chirp) error {
+	entities := extractEntities(chirp.Body)
+
+	handles := []string{}
+	for _, entity := range entities {
+		if entity.Type == entityMention {
+			handles = append(handles, strings.ToLower(entity.Value))
+		}
+	}
+
+	mentioned := map[string]uuid.UUID{}
+	if len(handles) > 0 {
+		users, err := q.ListUsersByHandles(ctx, handles)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			mentioned[strings.ToLower(user.Handle.String)] = user.ID
+		}
+	}
+
+	if err := q.DeleteChirpEntities(ctx, chirp.ID); err != nil {
+		return err
+	}
+
+	for _, entity := range entities {
+		mentionedUserID := uuid.NullUUID{}
+		if entity.Type == entityMention {
+			userID, ok := mentioned[strings.ToLower(entity.Value)]
+			if !ok {
+				continue
+			}
+			mentionedUserID = uuid.NullUUID{UUID: userID, Valid: true}
+		}
+
+		if err := q.CreateChirpEntity(ctx, database.CreateChirpEntityParams{
+			ChirpID:         chirp.ID,
+			Kind:            entity.Type,
+			Value:           entity.Value,
+			StartOffset:     int32(entity.Start),
+			EndOffset:       int32(entity.End),
+			MentionedUserID: mentionedUserID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtractEntities(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []Entity
+	}{
+		{
+			name: "No entities",
+			body: "I had something interesting for breakfast",
+			want: []Entity{},
+		},
+		{
+			name: "Hashtag and mention",
+			body: "#Go is fun, right @boots_bear?",
+			want: []Entity{
+				{Type: entityHashtag, Value: "Go", Start: 0, End: 3},
+				{Type: entityMention, Value: "boots_bear", Start: 18, End: 29},
+			},
+		},
+		{
+			name: "URL drops trailing punctuation",
+			body: "Read https://example.com/a#b.",
+			want: []Entity{
+				{Type: entityURL, Value: "https://example.com/a#b", Start: 5, End: 28},
+			},
+		},
+		{
+			name: "Offsets count runes",
+			body: "🐦🐦 #chirpy",
+			want: []Entity{
+				{Type: entityHashtag, Value: "chirpy", Start: 3, End: 10},
+			},
+		},
+		{
+			name: "Emails and mid-word hashes are ignored",
+			body: "mail me@example.com about issue#12",
+			want: []Entity{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractEntities(tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("extractEntities() got = %+v, want = %+v", got, tt.want)
+			}
+		})
+	}
+}
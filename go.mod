@@ -10,3 +10,9 @@ require (
 )
 
 require github.com/golang-jwt/jwt/v5 v5.2.1
+
+require github.com/rivo/uniseg v0.4.7
+
+require golang.org/x/text v0.21.0
+
+require golang.org/x/image v0.18.0
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerBlockUser(w http.ResponseWriter, req *http.Request) {
+	cfg.handleBlock(w, req, true)
+}
+
+func (cfg *apiConfig) handlerUnblockUser(w http.ResponseWriter, req *http.Request) {
+	cfg.handleBlock(w, req, false)
+}
+
+func (cfg *apiConfig) handlerMuteUser(w http.ResponseWriter, req *http.Request) {
+	cfg.handleMute(w, req, true)
+}
+
+func (cfg *apiConfig) handlerUnmuteUser(w http.ResponseWriter, req *http.Request) {
+	cfg.handleMute(w, req, false)
+}
+
+// handleBlock blocks or unblocks a user. A block also ends any follow
+// between the two users, in either direction.
+func (cfg *apiConfig) handleBlock(w http.ResponseWriter, req *http.Request, blocked bool) {
+	userID, otherUserID, ok := cfg.authorizeUserAction(w, req)
+	if !ok {
+		return
+	}
+
+	if !blocked {
+		if err := cfg.db.UnblockUser(req.Context(), database.UnblockUserParams{
+			BlockerID: userID,
+			BlockedID: otherUserID,
+		}); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't unblock the user", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't block the user", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	if err := qtx.BlockUser(req.Context(), database.BlockUserParams{
+		BlockerID: userID,
+		BlockedID: otherUserID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't block the user", err)
+		return
+	}
+
+	if err := qtx.RemoveFollowsBetween(req.Context(), database.RemoveFollowsBetweenParams{
+		UserID:      userID,
+		OtherUserID: otherUserID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't block the user", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't block the user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleMute mutes or unmutes a user. Muting is private: the muted user can
+// still interact as before, their chirps just stop showing up for the muter.
+func (cfg *apiConfig) handleMute(w http.ResponseWriter, req *http.Request, muted bool) {
+	userID, otherUserID, ok := cfg.authorizeUserAction(w, req)
+	if !ok {
+		return
+	}
+
+	var err error
+	if muted {
+		err = cfg.db.MuteUser(req.Context(), database.MuteUserParams{
+			MuterID: userID,
+			MutedID: otherUserID,
+		})
+	} else {
+		err = cfg.db.UnmuteUser(req.Context(), database.UnmuteUserParams{
+			MuterID: userID,
+			MutedID: otherUserID,
+		})
+	}
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update the mute", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authorizeUserAction authenticates the caller and resolves the {userID}
+// they're acting on, which must be someone else. It writes the error
+// response itself and reports whether the handler should go on.
+func (cfg *apiConfig) authorizeUserAction(w http.ResponseWriter, req *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	otherUserID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the userID", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+	if otherUserID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't do that to yourself", nil)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	if _, err := cfg.db.GetUserByID(req.Context(), otherUserID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the user", err)
+			return uuid.Nil, uuid.Nil, false
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get the user", err)
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, otherUserID, true
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerBookmarkChirp(w http.ResponseWriter, req *http.Request) {
+	cfg.handleBookmark(w, req, true)
+}
+
+func (cfg *apiConfig) handlerUnbookmarkChirp(w http.ResponseWriter, req *http.Request) {
+	cfg.handleBookmark(w, req, false)
+}
+
+func (cfg *apiConfig) handleBookmark(w http.ResponseWriter, req *http.Request, bookmarked bool) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the chirpID", err)
+		return
+	}
+
+	if !bookmarked {
+		if err := cfg.db.UnbookmarkChirp(req.Context(), database.UnbookmarkChirpParams{
+			UserID:  userID,
+			ChirpID: chirpID,
+		}); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't remove the bookmark", err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if _, err := cfg.db.GetChirp(req.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the requested chirp", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+
+	if err := cfg.db.BookmarkChirp(req.Context(), database.BookmarkChirpParams{
+		UserID:  userID,
+		ChirpID: chirpID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't bookmark the chirp", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerGetBookmarks lists the caller's bookmarks, most recently saved
+// first. Bookmarks are private, so there is no way to list anyone else's.
+func (cfg *apiConfig) handlerGetBookmarks(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	page, err := parseForwardPageParams(req.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	beforeCreatedAt, beforeID := page.cursorColumns()
+	bookmarks, err := cfg.db.ListBookmarks(req.Context(), database.ListBookmarksParams{
+		UserID:          userID,
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeID:        beforeID,
+		Limit:           int32(page.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get bookmarks", err)
+		return
+	}
+
+	bookmarks, next, _ := paginate(bookmarks, page, func(bookmark database.Bookmark) pageCursor {
+		return pageCursor{CreatedAt: bookmark.CreatedAt, ID: bookmark.ChirpID}
+	})
+
+	chirpIDs := make([]uuid.UUID, len(bookmarks))
+	for i, bookmark := range bookmarks {
+		chirpIDs[i] = bookmark.ChirpID
+	}
+
+	chirps := []database.Chirp{}
+	if len(chirpIDs) > 0 {
+		rows, err := cfg.db.ListChirpsByIDs(req.Context(), database.ListChirpsByIDsParams{
+			Ids:      chirpIDs,
+			ViewerID: userID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get bookmarks", err)
+			return
+		}
+
+		// Keep the bookmark order rather than whatever order the chirps
+		// came back in.
+		byID := make(map[uuid.UUID]database.Chirp, len(rows))
+		for _, chirp := range rows {
+			byID[chirp.ID] = chirp
+		}
+		for _, id := range chirpIDs {
+			if chirp, ok := byID[id]; ok {
+				chirps = append(chirps, chirp)
+			}
+		}
+	}
+
+	response, err := cfg.chirpResponses(req.Context(), userID, chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't build the chirp response", err)
+		return
+	}
+	response = withoutHiddenRechirps(chirps, response)
+
+	setPaginationHeaders(w, req, next, "")
+	respondWithJSON(w, http.StatusOK, response)
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"encoding/json"
-	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/Tanay-Verma/chirpy/internal/auth"
@@ -11,74 +14,188 @@ import (
 	"github.com/google/uuid"
 )
 
-type Chirp struct {
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
-	Body      string    `json:"body"`
-	ID        uuid.UUID `json:"id"`
-	UserID    uuid.UUID `json:"user_id"`
+type createChirpParams struct {
+	Body           string      `json:"body"`
+	InReplyToID    *uuid.UUID  `json:"in_reply_to_id"`
+	RechirpOfID    *uuid.UUID  `json:"rechirp_of_id"`
+	QuoteOfID      *uuid.UUID  `json:"quote_of_id"`
+	MediaIDs       []uuid.UUID `json:"media_ids"`
+	Poll           *pollParams `json:"poll"`
+	Visibility     string      `json:"visibility"`
+	ContentWarning string      `json:"content_warning"`
+	ExpiresIn      *int        `json:"expires_in"`
+	// PublishAt in the future saves the chirp as a scheduled draft instead.
+	PublishAt *time.Time `json:"publish_at"`
 }
 
 func (cfg *apiConfig) handlerCreateChirp(w http.ResponseWriter, req *http.Request) {
-	type parameters struct {
-		Body string `json:"body"`
-	}
-
 	token, err := auth.GetBearerToken(req.Header)
 	if err != nil {
 		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
 		return
 	}
 
-	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
 	if err != nil {
 		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
 		return
 	}
 
-	decoder := json.NewDecoder(req.Body)
-	params := parameters{}
-	if err := decoder.Decode(&params); err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+	params := createChirpParams{}
+	uploads := []*multipart.FileHeader{}
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		params, uploads, err = parseMultipartChirp(w, req)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Couldn't parse the form", err)
+			return
+		}
+	} else {
+		decoder := json.NewDecoder(req.Body)
+		if err := decoder.Decode(&params); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+			return
+		}
+	}
+
+	if len(params.MediaIDs)+len(uploads) > maxMediaPerChirp {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("A chirp can have at most %d attachments", maxMediaPerChirp), nil)
+		return
+	}
+
+	scheduled := params.PublishAt != nil && params.PublishAt.After(time.Now())
+	if scheduled && (params.RechirpOfID != nil || params.Poll != nil) {
+		respondWithError(w, http.StatusBadRequest, "Rechirps and polls can't be scheduled", nil)
 		return
 	}
 
-	cleaned, err := validateChirp(params.Body)
+	mediaIDs := append([]uuid.UUID{}, params.MediaIDs...)
+	for _, upload := range uploads {
+		attachment, err := cfg.storeUpload(req.Context(), userID, upload)
+		if err != nil {
+			status, message := uploadErrorStatus(err)
+			respondWithError(w, status, message, err)
+			return
+		}
+		mediaIDs = append(mediaIDs, attachment.ID)
+	}
+
+	if scheduled {
+		draft, err := cfg.createDraft(req.Context(), userID, draftParams{
+			Body:           params.Body,
+			InReplyToID:    params.InReplyToID,
+			QuoteOfID:      params.QuoteOfID,
+			MediaIDs:       mediaIDs,
+			PublishAt:      params.PublishAt,
+			Visibility:     params.Visibility,
+			ContentWarning: params.ContentWarning,
+			ExpiresIn:      params.ExpiresIn,
+		})
+		if err != nil {
+			respondWithChirpError(w, err)
+			return
+		}
+		respondWithJSON(w, http.StatusAccepted, draftFromDB(draft))
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
 	if err != nil {
-		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create the chirp", err)
 		return
 	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
 
-	newChirp, err := cfg.db.CreateChirp(req.Context(), database.CreateChirpParams{
-		Body:   cleaned,
-		UserID: userID,
+	newChirp, err := cfg.insertChirp(req.Context(), qtx, userID, chirpInput{
+		Body:           params.Body,
+		InReplyToID:    params.InReplyToID,
+		RechirpOfID:    params.RechirpOfID,
+		QuoteOfID:      params.QuoteOfID,
+		MediaIDs:       mediaIDs,
+		Poll:           params.Poll,
+		Visibility:     params.Visibility,
+		ContentWarning: params.ContentWarning,
+		ExpiresIn:      params.ExpiresIn,
 	})
 	if err != nil {
+		respondWithChirpError(w, err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't create the chirp", err)
 		return
 	}
 
-	respondWithJSON(w, http.StatusCreated, Chirp{
-		ID:        newChirp.ID,
-		CreatedAt: newChirp.CreatedAt,
-		UpdatedAt: newChirp.UpdatedAt,
-		Body:      newChirp.Body,
-		UserID:    newChirp.UserID,
-	})
+	response, err := cfg.chirpResponses(req.Context(), userID, []database.Chirp{newChirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't build the chirp response", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, response[0])
 }
 
-func validateChirp(body string) (string, error) {
-	const chirpMaxLength = 140
-	if len(body) > chirpMaxLength {
-		return "", errors.New("Chirp is too long")
+// parseMultipartChirp reads a chirp posted as a form, with images sent
+// directly as "media" file parts alongside any previously uploaded
+// "media_ids". A poll is passed as a JSON-encoded "poll" field.
+func parseMultipartChirp(w http.ResponseWriter, req *http.Request) (createChirpParams, []*multipart.FileHeader, error) {
+	req.Body = http.MaxBytesReader(w, req.Body, maxMediaPerChirp*maxMediaUploadBytes+maxMultipartMemory)
+	if err := req.ParseMultipartForm(maxMultipartMemory); err != nil {
+		return createChirpParams{}, nil, err
+	}
+
+	params := createChirpParams{
+		Body:           req.FormValue("body"),
+		Visibility:     req.FormValue("visibility"),
+		ContentWarning: req.FormValue("content_warning"),
+	}
+	for field, id := range map[string]**uuid.UUID{
+		"in_reply_to_id": &params.InReplyToID,
+		"rechirp_of_id":  &params.RechirpOfID,
+		"quote_of_id":    &params.QuoteOfID,
+	} {
+		value := req.FormValue(field)
+		if value == "" {
+			continue
+		}
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			return createChirpParams{}, nil, fmt.Errorf("invalid %s: %w", field, err)
+		}
+		*id = &parsed
+	}
+
+	if value := req.FormValue("publish_at"); value != "" {
+		publishAt, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return createChirpParams{}, nil, fmt.Errorf("invalid publish_at: %w", err)
+		}
+		params.PublishAt = &publishAt
+	}
+
+	if value := req.FormValue("expires_in"); value != "" {
+		expiresIn, err := strconv.Atoi(value)
+		if err != nil {
+			return createChirpParams{}, nil, fmt.Errorf("invalid expires_in: %w", err)
+		}
+		params.ExpiresIn = &expiresIn
+	}
+
+	if value := req.FormValue("poll"); value != "" {
+		params.Poll = &pollParams{}
+		if err := json.Unmarshal([]byte(value), params.Poll); err != nil {
+			return createChirpParams{}, nil, fmt.Errorf("invalid poll: %w", err)
+		}
 	}
 
-	restrictedWords := map[string]struct{}{
-		"kerfuffle": {},
-		"sharbert":  {},
-		"fornax":    {},
+	for _, value := range req.MultipartForm.Value["media_ids"] {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			return createChirpParams{}, nil, fmt.Errorf("invalid media_ids: %w", err)
+		}
+		params.MediaIDs = append(params.MediaIDs, parsed)
 	}
 
-	cleaned := getCleanedBody(body, restrictedWords)
-	return cleaned, nil
+	return params, req.MultipartForm.File["media"], nil
 }
@@ -15,7 +15,7 @@ func (cfg *apiConfig) handlerDeleteChirp(w http.ResponseWriter, req *http.Reques
 		return
 	}
 
-	userID, err := auth.ValidateJWT(token, cfg.jwtSecret)
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
 	if err != nil {
 		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
 		return
@@ -28,7 +28,17 @@ func (cfg *apiConfig) handlerDeleteChirp(w http.ResponseWriter, req *http.Reques
 		return
 	}
 
-	chirp, err := cfg.db.GetChirp(req.Context(), chirpID)
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete chirp", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	// Locking the chirp makes a reply being created wait on its foreign key
+	// check, so the reply check below can't miss one.
+	chirp, err := qtx.GetChirpForUpdate(req.Context(), chirpID)
 	if err != nil {
 		respondWithError(w, http.StatusNotFound, "Couldn't get chirp", err)
 		return
@@ -38,13 +48,45 @@ func (cfg *apiConfig) handlerDeleteChirp(w http.ResponseWriter, req *http.Reques
 		return
 	}
 
-	err = cfg.db.DeleteChirp(req.Context(), database.DeleteChirpParams{
-		ID:     chirpID,
-		UserID: userID,
-	})
+	hasReplies, err := qtx.HasReplies(req.Context(), chirpID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't check for replies", err)
+		return
+	}
+
+	blobKeys, err := deleteChirpMedia(req.Context(), qtx, chirpID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete chirp media", err)
+		return
+	}
+
+	// Replies keep pointing at a tombstone so the thread stays intact. It
+	// keeps nothing of what the chirp said, hashtags and mentions included.
+	if hasReplies {
+		if err := qtx.DeleteChirpEntities(req.Context(), chirpID); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't delete chirp entities", err)
+			return
+		}
+		err = qtx.TombstoneChirp(req.Context(), database.TombstoneChirpParams{
+			ID:     chirpID,
+			UserID: userID,
+		})
+	} else {
+		err = qtx.DeleteChirp(req.Context(), database.DeleteChirpParams{
+			ID:     chirpID,
+			UserID: userID,
+		})
+	}
 	if err != nil {
 		respondWithError(w, http.StatusForbidden, "Couldn't delete chirp", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete chirp", err)
+		return
 	}
+	cfg.deleteBlobs(req.Context(), blobKeys...)
 
 	w.WriteHeader(http.StatusNoContent)
 }
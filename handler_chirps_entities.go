@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerGetHashtagChirps(w http.ResponseWriter, req *http.Request) {
+	viewerID, err := cfg.viewerID(req)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	tag := req.PathValue("tag")
+	if tag == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing hashtag", nil)
+		return
+	}
+
+	page, err := parseForwardPageParams(req.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	beforeCreatedAt, beforeID := page.cursorColumns()
+	chirps, err := cfg.db.ListChirpsByHashtag(req.Context(), database.ListChirpsByHashtagParams{
+		Tag:             tag,
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeID:        beforeID,
+		Limit:           int32(page.Limit + 1),
+		ViewerID:        viewerID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirps", err)
+		return
+	}
+
+	cfg.respondWithChirpFeed(w, req, viewerID, chirps, page)
+}
+
+func (cfg *apiConfig) handlerGetUserMentions(w http.ResponseWriter, req *http.Request) {
+	viewerID, err := cfg.viewerID(req)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	userID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the userID", err)
+		return
+	}
+
+	page, err := parseForwardPageParams(req.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	beforeCreatedAt, beforeID := page.cursorColumns()
+	chirps, err := cfg.db.ListChirpsMentioningUser(req.Context(), database.ListChirpsMentioningUserParams{
+		UserID:          userID,
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeID:        beforeID,
+		Limit:           int32(page.Limit + 1),
+		ViewerID:        viewerID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirps", err)
+		return
+	}
+
+	cfg.respondWithChirpFeed(w, req, viewerID, chirps, page)
+}
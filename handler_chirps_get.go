@@ -1,58 +1,147 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
-	"sort"
 
+	"github.com/Tanay-Verma/chirpy/internal/database"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
 func (cfg *apiConfig) handlerGetChirps(w http.ResponseWriter, req *http.Request) {
-	chirps, err := cfg.db.GetChirps(req.Context())
+	viewerID, err := cfg.viewerID(req)
 	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirps", err)
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
 		return
 	}
 
-	authorID := uuid.Nil
+	authorID := uuid.NullUUID{}
 	authorIDString := req.URL.Query().Get("author_id")
 	if authorIDString != "" {
-		authorID, err = uuid.Parse(authorIDString)
+		id, err := uuid.Parse(authorIDString)
 		if err != nil {
 			respondWithError(w, http.StatusBadRequest, "Invalid author ID", err)
 			return
 		}
+		authorID = uuid.NullUUID{UUID: id, Valid: true}
 	}
 
 	sortOrder := req.URL.Query().Get("sort")
+	if sortOrder != "" && sortOrder != "asc" && sortOrder != "desc" {
+		respondWithError(w, http.StatusBadRequest, "Invalid sort order", nil)
+		return
+	}
 
-	response := []Chirp{}
-	for _, chirp := range chirps {
-		if authorID != uuid.Nil && chirp.UserID != authorID {
-			continue
-		}
+	page, err := parsePageParams(req.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
 
-		response = append(response, Chirp{
-			ID:        chirp.ID,
-			CreatedAt: chirp.CreatedAt,
-			UpdatedAt: chirp.UpdatedAt,
-			Body:      chirp.Body,
-			UserID:    chirp.UserID,
-		})
+	chirps, err := cfg.listChirps(req.Context(), viewerID, authorID, sortOrder == "desc", page)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirps", err)
+		return
 	}
 
-	if sortOrder == "desc" {
-		sort.Slice(response, func(i, j int) bool {
-			return response[i].CreatedAt.Compare(response[j].CreatedAt) >= 0
+	chirps, next, prev := paginate(chirps, page, chirpCursor)
+
+	// An author's pinned chirps lead the first page of their profile, and
+	// aren't repeated on any page further down.
+	if authorID.Valid {
+		pinned, err := cfg.db.ListPinnedChirps(req.Context(), database.ListPinnedChirpsParams{
+			UserID:   authorID.UUID,
+			ViewerID: viewerID,
 		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get pinned chirps", err)
+			return
+		}
+		chirps = withPinnedFirst(pinned, chirps, page.Cursor == nil)
 	}
 
+	response, err := cfg.chirpResponses(req.Context(), viewerID, chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't build the chirp response", err)
+		return
+	}
+	response = withoutHiddenRechirps(chirps, response)
+
+	response, err = cfg.withoutMutedKeywords(req.Context(), viewerID, response)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't apply muted keywords", err)
+		return
+	}
+
+	setPaginationHeaders(w, req, next, prev)
 	respondWithJSON(w, http.StatusOK, response)
 }
 
+// listChirps fetches one page of the chirps viewerID can see, plus a
+// lookahead row. Walking backwards through an ascending list is a descending
+// scan and vice versa.
+func (cfg *apiConfig) listChirps(ctx context.Context, viewerID uuid.UUID, authorID uuid.NullUUID, descending bool, page pageParams) ([]database.Chirp, error) {
+	cursorCreatedAt, cursorID := page.cursorColumns()
+	if page.Cursor != nil {
+		descending = descending != page.Cursor.Backward
+	}
+
+	if descending {
+		return cfg.db.ListChirpsDesc(ctx, database.ListChirpsDescParams{
+			AuthorID:        authorID,
+			BeforeCreatedAt: cursorCreatedAt,
+			BeforeID:        cursorID,
+			Limit:           int32(page.Limit + 1),
+			ViewerID:        viewerID,
+		})
+	}
+
+	return cfg.db.ListChirpsAsc(ctx, database.ListChirpsAscParams{
+		AuthorID:       authorID,
+		AfterCreatedAt: cursorCreatedAt,
+		AfterID:        cursorID,
+		Limit:          int32(page.Limit + 1),
+		ViewerID:       viewerID,
+	})
+}
+
+// withPinnedFirst drops pinned chirps from a page of chirps, and puts them
+// at its head if it's the first page.
+func withPinnedFirst(pinned, chirps []database.Chirp, firstPage bool) []database.Chirp {
+	if len(pinned) == 0 {
+		return chirps
+	}
+
+	isPinned := make(map[uuid.UUID]bool, len(pinned))
+	for _, chirp := range pinned {
+		isPinned[chirp.ID] = true
+	}
+
+	result := []database.Chirp{}
+	if firstPage {
+		result = append(result, pinned...)
+	}
+	for _, chirp := range chirps {
+		if !isPinned[chirp.ID] {
+			result = append(result, chirp)
+		}
+	}
+	return result
+}
+
+func chirpCursor(chirp database.Chirp) pageCursor {
+	return pageCursor{CreatedAt: chirp.CreatedAt, ID: chirp.ID}
+}
+
 func (cfg *apiConfig) handlerGetChirp(w http.ResponseWriter, req *http.Request) {
+	viewerID, err := cfg.viewerID(req)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
 	chirpIDString := req.PathValue("chirpID")
 
 	chirpID, err := uuid.Parse(chirpIDString)
@@ -61,7 +150,7 @@ func (cfg *apiConfig) handlerGetChirp(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	chirp, err := cfg.db.GetChirp(req.Context(), chirpID)
+	chirp, err := cfg.db.GetChirp(req.Context(), database.GetChirpParams{ID: chirpID, ViewerID: viewerID})
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
 			log.Println("DB error:", pqErr)
@@ -70,11 +159,11 @@ func (cfg *apiConfig) handlerGetChirp(w http.ResponseWriter, req *http.Request)
 		return
 	}
 
-	respondWithJSON(w, http.StatusOK, Chirp{
-		ID:        chirp.ID,
-		CreatedAt: chirp.CreatedAt,
-		UpdatedAt: chirp.UpdatedAt,
-		Body:      chirp.Body,
-		UserID:    chirp.UserID,
-	})
+	response, err := cfg.chirpResponses(req.Context(), viewerID, []database.Chirp{chirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't build the chirp response", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response[0])
 }
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func TestWithPinnedFirst(t *testing.T) {
+	a := database.Chirp{ID: uuid.New()}
+	b := database.Chirp{ID: uuid.New()}
+	c := database.Chirp{ID: uuid.New()}
+
+	cases := []struct {
+		name      string
+		pinned    []database.Chirp
+		chirps    []database.Chirp
+		firstPage bool
+		want      []database.Chirp
+	}{
+		{name: "no pins", chirps: []database.Chirp{a, b}, firstPage: true, want: []database.Chirp{a, b}},
+		{name: "first page", pinned: []database.Chirp{c, b}, chirps: []database.Chirp{a, b}, firstPage: true, want: []database.Chirp{c, b, a}},
+		{name: "later page", pinned: []database.Chirp{c, b}, chirps: []database.Chirp{a, b}, want: []database.Chirp{a}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := withPinnedFirst(tc.pinned, tc.chirps, tc.firstPage)
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d chirps, want %d", len(got), len(tc.want))
+			}
+			for i := range tc.want {
+				if got[i].ID != tc.want[i].ID {
+					t.Errorf("chirp %d: got %v, want %v", i, got[i].ID, tc.want[i].ID)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func (cfg *apiConfig) handlerVotePoll(w http.ResponseWriter, req *http.Request) {
+	type parameters struct {
+		Option *int32 `json:"option"`
+	}
+
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the chirpID", err)
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+	if params.Option == nil {
+		respondWithError(w, http.StatusBadRequest, "Missing option", nil)
+		return
+	}
+
+	if _, err := cfg.db.GetChirp(req.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the requested chirp", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+
+	poll, err := cfg.db.GetPoll(req.Context(), chirpID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "This chirp has no poll", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get the poll", err)
+		return
+	}
+	if poll.FinalizedAt.Valid || !time.Now().UTC().Before(poll.ClosesAt) {
+		respondWithError(w, http.StatusForbidden, "This poll is closed", nil)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't record the vote", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	voted, err := qtx.CreatePollVote(req.Context(), database.CreatePollVoteParams{
+		ChirpID:  chirpID,
+		UserID:   userID,
+		Position: *params.Option,
+	})
+	if err != nil {
+		// The vote references its option, so an unknown option fails the
+		// foreign key.
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			respondWithError(w, http.StatusBadRequest, "Invalid option", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't record the vote", err)
+		return
+	}
+	if voted == 0 {
+		respondWithError(w, http.StatusConflict, "You already voted in this poll", nil)
+		return
+	}
+
+	if err := qtx.IncrementPollOptionVotes(req.Context(), database.IncrementPollOptionVotesParams{
+		ChirpID:  chirpID,
+		Position: *params.Option,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't record the vote", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't record the vote", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
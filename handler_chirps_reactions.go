@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+var allowedReactions = map[string]struct{}{
+	"👍":  {},
+	"❤️": {},
+	"😂":  {},
+	"😮":  {},
+	"😢":  {},
+	"🎉":  {},
+}
+
+func (cfg *apiConfig) handlerLikeChirp(w http.ResponseWriter, req *http.Request) {
+	cfg.handleLike(w, req, true)
+}
+
+func (cfg *apiConfig) handlerUnlikeChirp(w http.ResponseWriter, req *http.Request) {
+	cfg.handleLike(w, req, false)
+}
+
+func (cfg *apiConfig) handleLike(w http.ResponseWriter, req *http.Request, liked bool) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the chirpID", err)
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(req.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the requested chirp", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+
+	// Taking a like or reaction back is always allowed, even once blocked.
+	if liked {
+		blocked, err := cfg.db.IsBlocked(req.Context(), database.IsBlockedParams{
+			BlockerID: chirp.UserID,
+			BlockedID: userID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't check for blocks", err)
+			return
+		}
+		if blocked {
+			respondWithError(w, http.StatusForbidden, "You can't interact with this user's chirps", nil)
+			return
+		}
+	}
+
+	if err := cfg.setLike(req.Context(), userID, chirp, liked); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update like", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setLike records or removes a like and keeps chirps.like_count in step with
+// it. Repeating either call is a no-op, so the counter only moves, and the
+// author is only notified, when a row actually changed.
+func (cfg *apiConfig) setLike(ctx context.Context, userID uuid.UUID, chirp database.Chirp, liked bool) error {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	var changed int64
+	delta := int32(1)
+	if liked {
+		changed, err = qtx.LikeChirp(ctx, database.LikeChirpParams{
+			ChirpID: chirp.ID,
+			UserID:  userID,
+		})
+	} else {
+		delta = -1
+		changed, err = qtx.UnlikeChirp(ctx, database.UnlikeChirpParams{
+			ChirpID: chirp.ID,
+			UserID:  userID,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if changed > 0 {
+		if err := qtx.AdjustLikeCount(ctx, database.AdjustLikeCountParams{
+			Delta: delta,
+			ID:    chirp.ID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if liked && changed > 0 {
+		if err := recordNotifications(ctx, qtx, notificationEvent{
+			Kind:        notificationLike,
+			RecipientID: chirp.UserID,
+			ActorID:     userID,
+			ChirpID:     uuid.NullUUID{UUID: chirp.ID, Valid: true},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (cfg *apiConfig) handlerAddReaction(w http.ResponseWriter, req *http.Request) {
+	type parameters struct {
+		Emoji string `json:"emoji"`
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	cfg.handleReaction(w, req, params.Emoji, true)
+}
+
+func (cfg *apiConfig) handlerRemoveReaction(w http.ResponseWriter, req *http.Request) {
+	cfg.handleReaction(w, req, req.PathValue("emoji"), false)
+}
+
+func (cfg *apiConfig) handleReaction(w http.ResponseWriter, req *http.Request, emoji string, reacted bool) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the chirpID", err)
+		return
+	}
+
+	if _, ok := allowedReactions[emoji]; !ok {
+		respondWithError(w, http.StatusBadRequest, "Unsupported reaction", nil)
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(req.Context(), database.GetChirpParams{ID: chirpID, ViewerID: userID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the requested chirp", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+
+	if reacted {
+		blocked, err := cfg.db.IsBlocked(req.Context(), database.IsBlockedParams{
+			BlockerID: chirp.UserID,
+			BlockedID: userID,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't check for blocks", err)
+			return
+		}
+		if blocked {
+			respondWithError(w, http.StatusForbidden, "You can't interact with this user's chirps", nil)
+			return
+		}
+	}
+
+	if err := cfg.setReaction(req.Context(), userID, chirpID, emoji, reacted); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update reaction", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setReaction is setLike for emoji reactions, counted per emoji in
+// chirps.reaction_counts.
+func (cfg *apiConfig) setReaction(ctx context.Context, userID, chirpID uuid.UUID, emoji string, reacted bool) error {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	var changed int64
+	delta := int32(1)
+	if reacted {
+		changed, err = qtx.AddReaction(ctx, database.AddReactionParams{
+			ChirpID: chirpID,
+			UserID:  userID,
+			Emoji:   emoji,
+		})
+	} else {
+		delta = -1
+		changed, err = qtx.RemoveReaction(ctx, database.RemoveReactionParams{
+			ChirpID: chirpID,
+			UserID:  userID,
+			Emoji:   emoji,
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if changed > 0 {
+		if err := qtx.AdjustReactionCount(ctx, database.AdjustReactionCountParams{
+			Emoji: emoji,
+			Delta: delta,
+			ID:    chirpID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
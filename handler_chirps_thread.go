@@ -0,0 +1,111 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+type ChirpThreadNode struct {
+	Chirp
+	Replies []ChirpThreadNode `json:"replies"`
+}
+
+func (cfg *apiConfig) handlerGetChirpThread(w http.ResponseWriter, req *http.Request) {
+	type response struct {
+		Ancestors []Chirp           `json:"ancestors"`
+		Chirp     Chirp             `json:"chirp"`
+		Replies   []ChirpThreadNode `json:"replies"`
+	}
+
+	viewerID, err := cfg.viewerID(req)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the chirpID", err)
+		return
+	}
+
+	chirp, err := cfg.db.GetChirp(req.Context(), database.GetChirpParams{ID: chirpID, ViewerID: viewerID})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the requested chirp", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+
+	rootID := chirp.ID
+	if chirp.RootID.Valid {
+		rootID = chirp.RootID.UUID
+	}
+
+	// The whole conversation shares a root, so one query fetches every chirp
+	// the tree can reach and the walk below stays in memory.
+	threadChirps, err := cfg.db.ListThreadChirps(req.Context(), database.ListThreadChirpsParams{
+		RootID:   rootID,
+		ViewerID: viewerID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get thread", err)
+		return
+	}
+
+	threadResponses, err := cfg.chirpResponses(req.Context(), viewerID, threadChirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't build the chirp response", err)
+		return
+	}
+
+	byID := map[uuid.UUID]Chirp{}
+	children := map[uuid.UUID][]Chirp{}
+	for _, c := range threadResponses {
+		byID[c.ID] = c
+		if c.InReplyToID != nil {
+			children[*c.InReplyToID] = append(children[*c.InReplyToID], c)
+		}
+	}
+
+	// The thread leaves out chirps from authors the viewer blocked or muted,
+	// including this one.
+	focal, ok := byID[chirp.ID]
+	if !ok {
+		respondWithError(w, http.StatusNotFound, "Couldn't find the requested chirp", nil)
+		return
+	}
+
+	ancestors := []Chirp{}
+	for parentID := focal.InReplyToID; parentID != nil; {
+		parent, ok := byID[*parentID]
+		if !ok {
+			break
+		}
+		ancestors = append([]Chirp{parent}, ancestors...)
+		parentID = parent.InReplyToID
+	}
+
+	respondWithJSON(w, http.StatusOK, response{
+		Ancestors: ancestors,
+		Chirp:     focal,
+		Replies:   buildThreadReplies(chirp.ID, children),
+	})
+}
+
+func buildThreadReplies(parentID uuid.UUID, children map[uuid.UUID][]Chirp) []ChirpThreadNode {
+	replies := []ChirpThreadNode{}
+	for _, child := range children[parentID] {
+		replies = append(replies, ChirpThreadNode{
+			Chirp:   child,
+			Replies: buildThreadReplies(child.ID, children),
+		})
+	}
+	return replies
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	chirpEditWindow    = 30 * time.Minute
+	chirpRedEditWindow = 2 * time.Hour
+)
+
+type ChirpRevision struct {
+	CreatedAt  time.Time `json:"created_at"`
+	ReplacedAt time.Time `json:"replaced_at"`
+	Body       string    `json:"body"`
+	ID         uuid.UUID `json:"id"`
+}
+
+func (cfg *apiConfig) handlerUpdateChirp(w http.ResponseWriter, req *http.Request) {
+	type parameters struct {
+		Body string `json:"body"`
+	}
+
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the chirpID", err)
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	user, err := cfg.db.GetUserByID(req.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get the user", err)
+		return
+	}
+
+	cleaned, err := cfg.validateChirp(params.Body, chirpLengthLimit(user.IsChirpyRed))
+	if err != nil {
+		respondWithChirpError(w, err)
+		return
+	}
+
+	editWindow := chirpEditWindow
+	if user.IsChirpyRed {
+		editWindow = chirpRedEditWindow
+	}
+
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update chirp", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	chirp, err := qtx.GetChirpForUpdate(req.Context(), chirpID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the requested chirp", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+	if chirp.UserID != userID {
+		respondWithError(w, http.StatusForbidden, "You can't edit this chirp", nil)
+		return
+	}
+	if chirp.RechirpOfID.Valid {
+		respondWithError(w, http.StatusBadRequest, "Rechirps can't be edited", nil)
+		return
+	}
+	if time.Since(chirp.CreatedAt) > editWindow {
+		respondWithError(w, http.StatusForbidden, "The editing window for this chirp has closed", nil)
+		return
+	}
+
+	if cleaned != chirp.Body {
+		if err := qtx.CreateChirpRevision(req.Context(), database.CreateChirpRevisionParams{
+			ChirpID:   chirp.ID,
+			Body:      chirp.Body,
+			CreatedAt: chirp.UpdatedAt,
+		}); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't save chirp revision", err)
+			return
+		}
+
+		chirp, err = qtx.UpdateChirpBody(req.Context(), database.UpdateChirpBodyParams{
+			ID:   chirp.ID,
+			Body: cleaned,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't update chirp", err)
+			return
+		}
+
+		if err := saveChirpEntities(req.Context(), qtx, chirp); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't save chirp entities", err)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update chirp", err)
+		return
+	}
+
+	response, err := cfg.chirpResponses(req.Context(), userID, []database.Chirp{chirp})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't build the chirp response", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, response[0])
+}
+
+func (cfg *apiConfig) handlerGetChirpRevisions(w http.ResponseWriter, req *http.Request) {
+	viewerID, err := cfg.viewerID(req)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	chirpID, err := uuid.Parse(req.PathValue("chirpID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the chirpID", err)
+		return
+	}
+
+	if _, err := cfg.db.GetChirp(req.Context(), database.GetChirpParams{ID: chirpID, ViewerID: viewerID}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the requested chirp", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp", err)
+		return
+	}
+
+	revisions, err := cfg.db.ListChirpRevisions(req.Context(), chirpID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get chirp revisions", err)
+		return
+	}
+
+	response := []ChirpRevision{}
+	for _, revision := range revisions {
+		response = append(response, ChirpRevision{
+			ID:         revision.ID,
+			CreatedAt:  revision.CreatedAt,
+			ReplacedAt: revision.ReplacedAt,
+			Body:       revision.Body,
+		})
+	}
+
+	respondWithJSON(w, http.StatusOK, response)
+}
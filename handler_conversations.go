@@ -0,0 +1,323 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// handlerCreateConversation starts a conversation with one or more other
+// users. Starting a direct conversation that already exists returns it
+// instead of opening a second one.
+func (cfg *apiConfig) handlerCreateConversation(w http.ResponseWriter, req *http.Request) {
+	type parameters struct {
+		MemberIDs       []uuid.UUID `json:"member_ids"`
+		Title           string      `json:"title"`
+		FilterProfanity bool        `json:"filter_profanity"`
+	}
+
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	others, err := conversationMemberIDs(userID, params.MemberIDs)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+	isGroup := len(others) > 1
+
+	title, err := conversationTitle(params.Title, isGroup)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	blocked, err := cfg.db.IsBlockedByAny(req.Context(), database.IsBlockedByAnyParams{
+		BlockerIds: others,
+		BlockedID:  userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't check for blocks", err)
+		return
+	}
+	if blocked {
+		respondWithError(w, http.StatusForbidden, "You can't message one of these users", nil)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create the conversation", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	// Two users only ever share one direct conversation. The lock keeps two
+	// requests that start it at once from both creating it.
+	if !isGroup {
+		pair := database.LockDirectConversationParams{
+			UserID:      userID,
+			OtherUserID: others[0],
+		}
+		if err := qtx.LockDirectConversation(req.Context(), pair); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't create the conversation", err)
+			return
+		}
+
+		existing, err := qtx.FindDirectConversation(req.Context(), database.FindDirectConversationParams(pair))
+		if err == nil {
+			cfg.respondWithConversation(w, req, http.StatusOK, userID, existing)
+			return
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get the conversation", err)
+			return
+		}
+	}
+
+	conversation, err := qtx.CreateConversation(req.Context(), database.CreateConversationParams{
+		IsGroup:         isGroup,
+		Title:           title,
+		FilterProfanity: params.FilterProfanity,
+		CreatedBy:       uuid.NullUUID{UUID: userID, Valid: true},
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create the conversation", err)
+		return
+	}
+
+	for _, memberID := range append([]uuid.UUID{userID}, others...) {
+		if err := qtx.AddConversationMember(req.Context(), database.AddConversationMemberParams{
+			ConversationID: conversation.ID,
+			UserID:         memberID,
+		}); err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+				respondWithError(w, http.StatusBadRequest, "Couldn't find one of the members", err)
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Couldn't create the conversation", err)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create the conversation", err)
+		return
+	}
+
+	cfg.respondWithConversation(w, req, http.StatusCreated, userID, conversation)
+}
+
+// handlerGetConversations lists the caller's conversations, most recently
+// active first.
+func (cfg *apiConfig) handlerGetConversations(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	page, err := parseForwardPageParams(req.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	beforeUpdatedAt, beforeID := page.cursorColumns()
+	conversations, err := cfg.db.ListConversations(req.Context(), database.ListConversationsParams{
+		UserID:          userID,
+		BeforeUpdatedAt: beforeUpdatedAt,
+		BeforeID:        beforeID,
+		Limit:           int32(page.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get conversations", err)
+		return
+	}
+
+	conversations, next, _ := paginate(conversations, page, func(conversation database.Conversation) pageCursor {
+		return pageCursor{CreatedAt: conversation.UpdatedAt, ID: conversation.ID}
+	})
+
+	response, err := cfg.conversationResponses(req.Context(), userID, conversations)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't build the conversation response", err)
+		return
+	}
+
+	setPaginationHeaders(w, req, next, "")
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+func (cfg *apiConfig) handlerGetConversation(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	conversationID, err := uuid.Parse(req.PathValue("conversationID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the conversationID", err)
+		return
+	}
+
+	conversation, err := cfg.db.GetConversation(req.Context(), database.GetConversationParams{
+		ID:     conversationID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the conversation", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get the conversation", err)
+		return
+	}
+
+	cfg.respondWithConversation(w, req, http.StatusOK, userID, conversation)
+}
+
+// handlerReadConversation marks everything in a conversation up to now as
+// read by the caller.
+func (cfg *apiConfig) handlerReadConversation(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	conversationID, err := uuid.Parse(req.PathValue("conversationID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the conversationID", err)
+		return
+	}
+
+	if _, err := cfg.db.GetConversation(req.Context(), database.GetConversationParams{
+		ID:     conversationID,
+		UserID: userID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the conversation", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get the conversation", err)
+		return
+	}
+
+	if err := cfg.db.MarkConversationRead(req.Context(), database.MarkConversationReadParams{
+		ConversationID: conversationID,
+		UserID:         userID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mark the conversation read", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerLeaveConversation removes the caller from a conversation. The
+// conversation and its messages go away with its last member.
+func (cfg *apiConfig) handlerLeaveConversation(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	conversationID, err := uuid.Parse(req.PathValue("conversationID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the conversationID", err)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't leave the conversation", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	left, err := qtx.LeaveConversation(req.Context(), database.LeaveConversationParams{
+		ConversationID: conversationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't leave the conversation", err)
+		return
+	}
+	if left == 0 {
+		respondWithError(w, http.StatusNotFound, "Couldn't find the conversation", nil)
+		return
+	}
+
+	if err := qtx.DeleteEmptyConversation(req.Context(), conversationID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't leave the conversation", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't leave the conversation", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) respondWithConversation(w http.ResponseWriter, req *http.Request, status int, userID uuid.UUID, conversation database.Conversation) {
+	response, err := cfg.conversationResponses(req.Context(), userID, []database.Conversation{conversation})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't build the conversation response", err)
+		return
+	}
+	respondWithJSON(w, status, response[0])
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerCreateDraft(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := draftParams{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	draft, err := cfg.createDraft(req.Context(), userID, params)
+	if err != nil {
+		respondWithChirpError(w, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, draftFromDB(draft))
+}
+
+func (cfg *apiConfig) handlerGetDrafts(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	page, err := parseForwardPageParams(req.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	beforeCreatedAt, beforeID := page.cursorColumns()
+	drafts, err := cfg.db.ListDrafts(req.Context(), database.ListDraftsParams{
+		UserID:          userID,
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeID:        beforeID,
+		Limit:           int32(page.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get drafts", err)
+		return
+	}
+
+	drafts, next, _ := paginate(drafts, page, func(draft database.Draft) pageCursor {
+		return pageCursor{CreatedAt: draft.CreatedAt, ID: draft.ID}
+	})
+
+	response := make([]Draft, len(drafts))
+	for i, draft := range drafts {
+		response[i] = draftFromDB(draft)
+	}
+
+	setPaginationHeaders(w, req, next, "")
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+func (cfg *apiConfig) handlerGetDraft(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	draftID, err := uuid.Parse(req.PathValue("draftID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the draftID", err)
+		return
+	}
+
+	draft, err := cfg.db.GetDraft(req.Context(), database.GetDraftParams{
+		ID:     draftID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the draft", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get the draft", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, draftFromDB(draft))
+}
+
+// handlerUpdateDraft replaces a draft's content and schedule. A failed draft
+// is rescheduled by saving it again; published drafts can't change.
+func (cfg *apiConfig) handlerUpdateDraft(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	draftID, err := uuid.Parse(req.PathValue("draftID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the draftID", err)
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := draftParams{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	if err := cfg.validateDraft(req.Context(), userID, params); err != nil {
+		respondWithChirpError(w, err)
+		return
+	}
+
+	draft, err := cfg.db.UpdateDraft(req.Context(), database.UpdateDraftParams{
+		ID:               draftID,
+		UserID:           userID,
+		Body:             params.Body,
+		InReplyToID:      nullUUID(params.InReplyToID),
+		QuoteOfID:        nullUUID(params.QuoteOfID),
+		MediaIds:         append([]uuid.UUID{}, params.MediaIDs...),
+		PublishAt:        params.publishAt(),
+		Status:           params.status(),
+		Visibility:       params.visibility(),
+		ContentWarning:   params.contentWarning(),
+		ExpiresInSeconds: params.expiresIn(),
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't update the draft", err)
+			return
+		}
+		// Nothing matched: tell a missing draft apart from a published one.
+		if _, err := cfg.db.GetDraft(req.Context(), database.GetDraftParams{
+			ID:     draftID,
+			UserID: userID,
+		}); err == nil {
+			respondWithError(w, http.StatusConflict, "This draft has already been published", nil)
+			return
+		}
+		respondWithError(w, http.StatusNotFound, "Couldn't find the draft", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusOK, draftFromDB(draft))
+}
+
+func (cfg *apiConfig) handlerDeleteDraft(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	draftID, err := uuid.Parse(req.PathValue("draftID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the draftID", err)
+		return
+	}
+
+	deleted, err := cfg.db.DeleteDraft(req.Context(), database.DeleteDraftParams{
+		ID:     draftID,
+		UserID: userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete the draft", err)
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Couldn't find the draft", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *apiConfig) handlerFollowUser(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	followeeID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the userID", err)
+		return
+	}
+	if followeeID == userID {
+		respondWithError(w, http.StatusBadRequest, "You can't follow yourself", nil)
+		return
+	}
+
+	if _, err := cfg.db.GetUserByID(req.Context(), followeeID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the user", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get the user", err)
+		return
+	}
+
+	blocked, err := cfg.db.IsBlocked(req.Context(), database.IsBlockedParams{
+		BlockerID: followeeID,
+		BlockedID: userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't check for blocks", err)
+		return
+	}
+	if blocked {
+		respondWithError(w, http.StatusForbidden, "You can't follow this user", nil)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't follow the user", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	followed, err := qtx.FollowUser(req.Context(), database.FollowUserParams{
+		FollowerID: userID,
+		FolloweeID: followeeID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't follow the user", err)
+		return
+	}
+
+	if followed > 0 {
+		if err := recordNotifications(req.Context(), qtx, notificationEvent{
+			Kind:        notificationFollow,
+			RecipientID: followeeID,
+			ActorID:     userID,
+		}); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't follow the user", err)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't follow the user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handlerUnfollowUser(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	followeeID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the userID", err)
+		return
+	}
+
+	if _, err := cfg.db.UnfollowUser(req.Context(), database.UnfollowUserParams{
+		FollowerID: userID,
+		FolloweeID: followeeID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unfollow the user", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handlerGetFollowers(w http.ResponseWriter, req *http.Request) {
+	userID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the userID", err)
+		return
+	}
+
+	page, err := parseForwardPageParams(req.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	beforeFollowedAt, beforeID := page.cursorColumns()
+	followers, err := cfg.db.ListFollowers(req.Context(), database.ListFollowersParams{
+		UserID:           userID,
+		BeforeFollowedAt: beforeFollowedAt,
+		BeforeID:         beforeID,
+		Limit:            int32(page.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get followers", err)
+		return
+	}
+
+	respondWithFollowList(w, req, followers, page)
+}
+
+func (cfg *apiConfig) handlerGetFollowing(w http.ResponseWriter, req *http.Request) {
+	userID, err := uuid.Parse(req.PathValue("userID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the userID", err)
+		return
+	}
+
+	page, err := parseForwardPageParams(req.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	beforeFollowedAt, beforeID := page.cursorColumns()
+	following, err := cfg.db.ListFollowing(req.Context(), database.ListFollowingParams{
+		UserID:           userID,
+		BeforeFollowedAt: beforeFollowedAt,
+		BeforeID:         beforeID,
+		Limit:            int32(page.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get followed users", err)
+		return
+	}
+
+	rows := make([]database.ListFollowersRow, 0, len(following))
+	for _, row := range following {
+		rows = append(rows, database.ListFollowersRow(row))
+	}
+	respondWithFollowList(w, req, rows, page)
+}
+
+func respondWithFollowList(w http.ResponseWriter, req *http.Request, rows []database.ListFollowersRow, page pageParams) {
+	rows, next, _ := paginate(rows, page, func(row database.ListFollowersRow) pageCursor {
+		return pageCursor{CreatedAt: row.FollowedAt, ID: row.ID}
+	})
+
+	response := []User{}
+	for _, row := range rows {
+		response = append(response, User{
+			ID:          row.ID,
+			CreatedAt:   row.CreatedAt,
+			UpdatedAt:   row.UpdatedAt,
+			IsChirpyRed: row.IsChirpyRed,
+			Handle:      row.Handle.String,
+		})
+	}
+
+	setPaginationHeaders(w, req, next, "")
+	respondWithJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/blobstore"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+)
+
+// handlerUploadMedia stores an image ahead of the chirp it will be attached
+// to, so clients can upload while the user is still typing.
+func (cfg *apiConfig) handlerUploadMedia(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	req.Body = http.MaxBytesReader(w, req.Body, maxMediaUploadBytes+maxMultipartMemory)
+	if err := req.ParseMultipartForm(maxMultipartMemory); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the form", err)
+		return
+	}
+
+	_, header, err := req.FormFile("file")
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Missing file", err)
+		return
+	}
+
+	attachment, err := cfg.storeUpload(req.Context(), userID, header)
+	if err != nil {
+		status, message := uploadErrorStatus(err)
+		respondWithError(w, status, message, err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, mediaAttachmentFromDB(attachment))
+}
+
+// handlerGetMedia serves an uploaded file to whoever can see the chirp it's
+// attached to. Until it's attached, only the uploader can see it.
+func (cfg *apiConfig) handlerGetMedia(w http.ResponseWriter, req *http.Request) {
+	key := req.PathValue("key")
+
+	viewerID, err := cfg.viewerID(req)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	attachment, err := cfg.db.GetMediaAttachmentByKey(req.Context(), key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the file", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get the file", err)
+		return
+	}
+
+	// Only files on a public chirp that won't expire can be cached by
+	// anyone; the rest can stop being visible later.
+	cacheControl := "private, no-cache"
+	if !attachment.ChirpID.Valid {
+		if attachment.UserID != viewerID {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the file", nil)
+			return
+		}
+	} else {
+		chirp, err := cfg.db.GetChirp(req.Context(), database.GetChirpParams{
+			ID:       attachment.ChirpID.UUID,
+			ViewerID: viewerID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				respondWithError(w, http.StatusNotFound, "Couldn't find the file", err)
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get the file", err)
+			return
+		}
+		if chirp.Visibility == visibilityPublic && !chirp.ExpiresAt.Valid {
+			// Keys are random and never reused, so the content behind one
+			// never changes.
+			cacheControl = "public, max-age=31536000, immutable"
+		}
+	}
+
+	blob, err := cfg.blobs.Open(req.Context(), key)
+	if err != nil {
+		if errors.Is(err, blobstore.ErrNotFound) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the file", err)
+			return
+		}
+		respondWithError(w, http.StatusNotFound, "Couldn't open the file", err)
+		return
+	}
+	defer blob.Close()
+
+	w.Header().Set("Content-Type", mime.TypeByExtension(path.Ext(key)))
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	io.Copy(w, blob)
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerSendMessage posts a message to a conversation the caller is in.
+// Sending counts as having read the conversation up to that point.
+func (cfg *apiConfig) handlerSendMessage(w http.ResponseWriter, req *http.Request) {
+	type parameters struct {
+		Body string `json:"body"`
+	}
+
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	conversationID, err := uuid.Parse(req.PathValue("conversationID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the conversationID", err)
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	conversation, err := cfg.db.GetConversation(req.Context(), database.GetConversationParams{
+		ID:     conversationID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the conversation", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get the conversation", err)
+		return
+	}
+
+	// A group with someone in it who blocked the sender is read-only for
+	// them, the same as a direct conversation with the blocker.
+	blocked, err := cfg.db.IsBlockedInConversation(req.Context(), database.IsBlockedInConversationParams{
+		ConversationID: conversationID,
+		UserID:         userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't check for blocks", err)
+		return
+	}
+	if blocked {
+		respondWithError(w, http.StatusForbidden, "You can't send messages to this conversation", nil)
+		return
+	}
+
+	body, err := cfg.validateMessage(params.Body, conversation.FilterProfanity)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't send the message", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	message, err := qtx.CreateMessage(req.Context(), database.CreateMessageParams{
+		ConversationID: conversationID,
+		SenderID:       uuid.NullUUID{UUID: userID, Valid: true},
+		Body:           body,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't send the message", err)
+		return
+	}
+
+	if err := qtx.TouchConversation(req.Context(), conversationID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't send the message", err)
+		return
+	}
+
+	if err := qtx.MarkConversationRead(req.Context(), database.MarkConversationReadParams{
+		ConversationID: conversationID,
+		UserID:         userID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't send the message", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't send the message", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, messageFromDB(message))
+}
+
+// handlerGetMessages lists a conversation's messages, newest first. Reading
+// them doesn't mark them read; clients do that explicitly.
+func (cfg *apiConfig) handlerGetMessages(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	conversationID, err := uuid.Parse(req.PathValue("conversationID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the conversationID", err)
+		return
+	}
+
+	page, err := parseForwardPageParams(req.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	if _, err := cfg.db.GetConversation(req.Context(), database.GetConversationParams{
+		ID:     conversationID,
+		UserID: userID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusNotFound, "Couldn't find the conversation", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get the conversation", err)
+		return
+	}
+
+	beforeCreatedAt, beforeID := page.cursorColumns()
+	messages, err := cfg.db.ListMessages(req.Context(), database.ListMessagesParams{
+		ConversationID:  conversationID,
+		BeforeCreatedAt: beforeCreatedAt,
+		BeforeID:        beforeID,
+		Limit:           int32(page.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get messages", err)
+		return
+	}
+
+	messages, next, _ := paginate(messages, page, func(message database.Message) pageCursor {
+		return pageCursor{CreatedAt: message.CreatedAt, ID: message.ID}
+	})
+
+	response := make([]Message, 0, len(messages))
+	for _, message := range messages {
+		response = append(response, messageFromDB(message))
+	}
+
+	setPaginationHeaders(w, req, next, "")
+	respondWithJSON(w, http.StatusOK, response)
+}
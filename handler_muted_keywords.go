@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerGetMutedKeywords lists the caller's muted keywords that haven't
+// expired yet.
+func (cfg *apiConfig) handlerGetMutedKeywords(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	keywords, err := cfg.db.ListMutedKeywords(req.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get muted keywords", err)
+		return
+	}
+
+	response := make([]MutedKeyword, len(keywords))
+	for i, keyword := range keywords {
+		response[i] = mutedKeywordFromDB(keyword)
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// handlerCreateMutedKeyword mutes a word or phrase. Muting the same phrase
+// again replaces its settings. whole_word defaults to true, and expires_in is
+// in seconds.
+func (cfg *apiConfig) handlerCreateMutedKeyword(w http.ResponseWriter, req *http.Request) {
+	type parameters struct {
+		Phrase    string `json:"phrase"`
+		WholeWord *bool  `json:"whole_word"`
+		ExpiresIn *int   `json:"expires_in"`
+	}
+
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	decoder := json.NewDecoder(req.Body)
+	params := parameters{}
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	phrase, err := validateMutedPhrase(params.Phrase)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	expiresAt, err := mutedKeywordExpiresAt(time.Now(), params.ExpiresIn)
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	wholeWord := true
+	if params.WholeWord != nil {
+		wholeWord = *params.WholeWord
+	}
+
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mute the keyword", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	// Locking the user serializes concurrent mutes, so the limit holds.
+	if err := qtx.LockUser(req.Context(), userID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mute the keyword", err)
+		return
+	}
+
+	// Expired keywords no longer count toward the limit; clearing them out
+	// here keeps them from piling up.
+	if err := qtx.DeleteExpiredMutedKeywords(req.Context(), userID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mute the keyword", err)
+		return
+	}
+
+	keyword, err := qtx.CreateMutedKeyword(req.Context(), database.CreateMutedKeywordParams{
+		UserID:    userID,
+		Phrase:    phrase,
+		WholeWord: wholeWord,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mute the keyword", err)
+		return
+	}
+
+	// Counting after the insert lets a phrase that's already muted be
+	// updated even at the limit.
+	count, err := qtx.CountMutedKeywords(req.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't count muted keywords", err)
+		return
+	}
+	if count > maxMutedKeywords {
+		respondWithError(w, http.StatusBadRequest, fmt.Sprintf("You can mute at most %d keywords", maxMutedKeywords), nil)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mute the keyword", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, mutedKeywordFromDB(keyword))
+}
+
+func (cfg *apiConfig) handlerDeleteMutedKeyword(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	keywordID, err := uuid.Parse(req.PathValue("keywordID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the keywordID", err)
+		return
+	}
+
+	deleted, err := cfg.db.DeleteMutedKeyword(req.Context(), database.DeleteMutedKeywordParams{
+		ID:     keywordID,
+		UserID: userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't unmute the keyword", err)
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Couldn't find the muted keyword", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
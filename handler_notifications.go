@@ -0,0 +1,122 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerGetNotifications lists the caller's notifications, most recently
+// updated first. The total number of unread ones is sent in X-Unread-Count.
+func (cfg *apiConfig) handlerGetNotifications(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	page, err := parseForwardPageParams(req.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	beforeUpdatedAt, beforeID := page.cursorColumns()
+	notifications, err := cfg.db.ListNotifications(req.Context(), database.ListNotificationsParams{
+		UserID:          userID,
+		BeforeUpdatedAt: beforeUpdatedAt,
+		BeforeID:        beforeID,
+		Limit:           int32(page.Limit + 1),
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get notifications", err)
+		return
+	}
+
+	notifications, next, _ := paginate(notifications, page, func(notification database.Notification) pageCursor {
+		return pageCursor{CreatedAt: notification.UpdatedAt, ID: notification.ID}
+	})
+
+	response, err := cfg.notificationResponses(req.Context(), notifications)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't build the notification response", err)
+		return
+	}
+
+	unread, err := cfg.db.CountUnreadNotifications(req.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't count unread notifications", err)
+		return
+	}
+
+	w.Header().Set("X-Unread-Count", strconv.FormatInt(unread, 10))
+	setPaginationHeaders(w, req, next, "")
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// handlerReadNotification marks one notification read. Later events of the
+// same kind start a new notification rather than folding into it.
+func (cfg *apiConfig) handlerReadNotification(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	notificationID, err := uuid.Parse(req.PathValue("notificationID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the notificationID", err)
+		return
+	}
+
+	marked, err := cfg.db.MarkNotificationRead(req.Context(), database.MarkNotificationReadParams{
+		ID:     notificationID,
+		UserID: userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mark the notification read", err)
+		return
+	}
+	if marked == 0 {
+		respondWithError(w, http.StatusNotFound, "Couldn't find the notification", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *apiConfig) handlerReadAllNotifications(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	if err := cfg.db.MarkAllNotificationsRead(req.Context(), userID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't mark notifications read", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -1,11 +1,24 @@
 package main
 
 import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
 )
 
+// handlerRefresh swaps a refresh token for a new access token and the next
+// refresh token in its family. Each refresh token works once: presenting one
+// that was already swapped means it leaked, so the whole family is revoked
+// and whoever holds it has to log in again. The exception is a retry within
+// refreshReuseGrace, such as a second tab refreshing at the same moment,
+// which gets the same successor back as long as nobody has used it yet.
 func (cfg *apiConfig) handlerRefresh(w http.ResponseWriter, req *http.Request) {
 	refreshToken, err := auth.GetBearerToken(req.Header)
 	if err != nil {
@@ -13,25 +26,128 @@ func (cfg *apiConfig) handlerRefresh(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	user, err := cfg.db.GetUserFromRefreshToken(req.Context(), refreshToken)
+	lookupID, tokenHash, err := auth.ParseRefreshToken(refreshToken)
 	if err != nil {
 		respondWithError(w, http.StatusUnauthorized, "Couldn't get user for refresh token", err)
 		return
 	}
 
-	jwtToken, err := auth.MakeJWT(user.ID, cfg.jwtSecret, expiresIn)
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
 	if err != nil {
-		respondWithError(w, http.StatusUnauthorized, "Couldn't validate token", err)
+		respondWithError(w, http.StatusInternalServerError, "Couldn't refresh the session", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	stored, err := qtx.GetRefreshTokenForUpdate(req.Context(), database.GetRefreshTokenForUpdateParams{
+		LookupID:  lookupID,
+		TokenHash: tokenHash,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithError(w, http.StatusUnauthorized, "Couldn't get user for refresh token", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't refresh the session", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	successor := auth.NextRefreshToken(refreshToken, cfg.jwtSecret)
+
+	if stored.RotatedAt.Valid && !stored.RevokedAt.Valid {
+		if now.Sub(stored.RotatedAt.Time) < refreshReuseGrace {
+			live, err := refreshTokenLive(req.Context(), qtx, successor, now)
+			if err != nil {
+				respondWithError(w, http.StatusInternalServerError, "Couldn't refresh the session", err)
+				return
+			}
+			if live {
+				cfg.respondWithRefreshedTokens(w, tx, stored.UserID, successor)
+				return
+			}
+		}
+
+		revoked, err := qtx.RevokeRefreshTokenFamily(req.Context(), stored.FamilyID)
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't refresh the session", err)
+			return
+		}
+		if err := tx.Commit(); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't refresh the session", err)
+			return
+		}
+		log.Printf("SECURITY: refresh token reused for user %s; revoked %d token(s) in family %s", stored.UserID, revoked, stored.FamilyID)
+		respondWithError(w, http.StatusUnauthorized, "Refresh token was already used", nil)
+		return
+	}
+
+	if stored.RevokedAt.Valid || !stored.ExpiresAt.After(now) {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't get user for refresh token", nil)
+		return
+	}
+
+	if err := qtx.RotateRefreshToken(req.Context(), database.RotateRefreshTokenParams{
+		RotatedAt: now,
+		LookupID:  stored.LookupID,
+	}); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't refresh the session", err)
+		return
+	}
+
+	if err := storeRefreshToken(req.Context(), qtx, successor, stored.UserID, stored.FamilyID, stored.SessionStartedAt, sessionClientFromRequest(req)); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't save refresh token", err)
+		return
+	}
+
+	cfg.respondWithRefreshedTokens(w, tx, stored.UserID, successor)
+}
+
+// respondWithRefreshedTokens signs an access token and only then commits
+// tx, so a client is never left without the refresh token that tx stored.
+func (cfg *apiConfig) respondWithRefreshedTokens(w http.ResponseWriter, tx *sql.Tx, userID uuid.UUID, refreshToken string) {
+	jwtToken, err := cfg.jwtKeys().MakeJWT(userID, expiresIn)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create the access token", err)
+		return
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't refresh the session", err)
 		return
 	}
 
 	respondWithJSON(w, http.StatusOK, struct {
-		Token string `json:"token"`
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
 	}{
-		Token: jwtToken,
+		Token:        jwtToken,
+		RefreshToken: refreshToken,
 	})
 }
 
+// refreshTokenLive reports whether token is stored and can still be
+// exchanged.
+func refreshTokenLive(ctx context.Context, q *database.Queries, token string, now time.Time) (bool, error) {
+	lookupID, tokenHash, err := auth.ParseRefreshToken(token)
+	if err != nil {
+		return false, err
+	}
+
+	stored, err := q.GetRefreshTokenForUpdate(ctx, database.GetRefreshTokenForUpdateParams{
+		LookupID:  lookupID,
+		TokenHash: tokenHash,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !stored.RevokedAt.Valid && !stored.RotatedAt.Valid && stored.ExpiresAt.After(now), nil
+}
+
 func (cfg *apiConfig) handlerRevoke(w http.ResponseWriter, req *http.Request) {
 	refreshToken, err := auth.GetBearerToken(req.Header)
 	if err != nil {
@@ -39,10 +155,54 @@ func (cfg *apiConfig) handlerRevoke(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	if err := cfg.db.RevokeRefreshToken(req.Context(), refreshToken); err != nil {
+	lookupID, tokenHash, err := auth.ParseRefreshToken(refreshToken)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't parse refresh token", err)
+		return
+	}
+
+	if err := cfg.db.RevokeRefreshToken(req.Context(), database.RevokeRefreshTokenParams{
+		LookupID:  lookupID,
+		TokenHash: tokenHash,
+	}); err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't revoke session", err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// issueRefreshToken creates a new refresh token in familyID and stores its
+// hash, along with when the session started and the client it went to. Pass
+// uuid.New() and the current time to start a session at login.
+func issueRefreshToken(ctx context.Context, q *database.Queries, userID, familyID uuid.UUID, startedAt time.Time, client sessionClient) (string, error) {
+	refreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	if err := storeRefreshToken(ctx, q, refreshToken, userID, familyID, startedAt, client); err != nil {
+		return "", err
+	}
+	return refreshToken, nil
+}
+
+// storeRefreshToken saves the hash of refreshToken in familyID.
+func storeRefreshToken(ctx context.Context, q *database.Queries, refreshToken string, userID, familyID uuid.UUID, startedAt time.Time, client sessionClient) error {
+	lookupID, tokenHash, err := auth.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return err
+	}
+
+	_, err = q.CreateRefreshToken(ctx, database.CreateRefreshTokenParams{
+		LookupID:         lookupID,
+		TokenHash:        tokenHash,
+		UserID:           userID,
+		ExpiresAt:        time.Now().Add(refreshTokenExpiresIn),
+		FamilyID:         familyID,
+		SessionStartedAt: startedAt,
+		UserAgent:        client.UserAgent,
+		Ip:               client.IP,
+	})
+	return err
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/Tanay-Verma/chirpy/internal/profanity"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+type RestrictedWord struct {
+	ID        uuid.UUID `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	Pattern   string    `json:"pattern"`
+}
+
+// authorizeAdmin checks the ApiKey header against ADMIN_KEY. The admin API is
+// closed entirely when no key is configured.
+func (cfg *apiConfig) authorizeAdmin(w http.ResponseWriter, req *http.Request) bool {
+	if cfg.adminKey == "" {
+		respondWithError(w, http.StatusForbidden, "Admin API is disabled", nil)
+		return false
+	}
+
+	apiKey, err := auth.GetAPIKey(req.Header)
+	if err != nil || apiKey != cfg.adminKey {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate API key", err)
+		return false
+	}
+	return true
+}
+
+func (cfg *apiConfig) handlerGetRestrictedWords(w http.ResponseWriter, req *http.Request) {
+	if !cfg.authorizeAdmin(w, req) {
+		return
+	}
+
+	words, err := cfg.db.ListRestrictedWords(req.Context())
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get restricted words", err)
+		return
+	}
+
+	response := make([]RestrictedWord, len(words))
+	for i, word := range words {
+		response[i] = restrictedWordFromDB(word)
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+func (cfg *apiConfig) handlerCreateRestrictedWord(w http.ResponseWriter, req *http.Request) {
+	type parameters struct {
+		Pattern string `json:"pattern"`
+	}
+
+	if !cfg.authorizeAdmin(w, req) {
+		return
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(req.Body)
+	if err := decoder.Decode(&params); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't decode parameters", err)
+		return
+	}
+
+	if err := profanity.ValidateRule(params.Pattern); err != nil {
+		respondWithError(w, http.StatusBadRequest, "Invalid pattern", err)
+		return
+	}
+
+	word, err := cfg.db.CreateRestrictedWord(req.Context(), params.Pattern)
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			respondWithError(w, http.StatusConflict, "Pattern already exists", err)
+			return
+		}
+		respondWithError(w, http.StatusInternalServerError, "Couldn't create restricted word", err)
+		return
+	}
+
+	if err := cfg.reloadProfanityFilter(req.Context()); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't reload restricted words", err)
+		return
+	}
+
+	respondWithJSON(w, http.StatusCreated, restrictedWordFromDB(word))
+}
+
+func (cfg *apiConfig) handlerDeleteRestrictedWord(w http.ResponseWriter, req *http.Request) {
+	if !cfg.authorizeAdmin(w, req) {
+		return
+	}
+
+	wordID, err := uuid.Parse(req.PathValue("wordID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the wordID", err)
+		return
+	}
+
+	deleted, err := cfg.db.DeleteRestrictedWord(req.Context(), wordID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't delete restricted word", err)
+		return
+	}
+	if deleted == 0 {
+		respondWithError(w, http.StatusNotFound, "Couldn't find restricted word", nil)
+		return
+	}
+
+	if err := cfg.reloadProfanityFilter(req.Context()); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't reload restricted words", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func restrictedWordFromDB(word database.RestrictedWord) RestrictedWord {
+	return RestrictedWord{
+		ID:        word.ID,
+		CreatedAt: word.CreatedAt,
+		Pattern:   word.Pattern,
+	}
+}
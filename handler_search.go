@@ -0,0 +1,132 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const searchUsersLimit = 5
+
+func (cfg *apiConfig) handlerSearch(w http.ResponseWriter, req *http.Request) {
+	type response struct {
+		Chirps []Chirp `json:"chirps"`
+		Users  []User  `json:"users"`
+	}
+
+	viewerID, err := cfg.viewerID(req)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	q := strings.TrimSpace(req.URL.Query().Get("q"))
+	if q == "" {
+		respondWithError(w, http.StatusBadRequest, "Missing search query", nil)
+		return
+	}
+
+	page, err := parseForwardPageParams(req.URL.Query())
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, err.Error(), err)
+		return
+	}
+
+	// Ranked results have no stable key to seek from, so search pages by
+	// offset instead.
+	offset := 0
+	if page.Cursor != nil {
+		if page.Cursor.Offset == 0 {
+			respondWithError(w, http.StatusBadRequest, "Invalid cursor", nil)
+			return
+		}
+		offset = page.Cursor.Offset
+	}
+
+	query := parseSearchQuery(q)
+
+	authorID := uuid.NullUUID{}
+	if query.From != "" {
+		// from: only takes a handle. Handles can't contain "@", so an email
+		// matches nobody rather than revealing whether it has an account.
+		err := sql.ErrNoRows
+		var author database.User
+		if !strings.Contains(query.From, "@") {
+			author, err = cfg.db.GetUserByHandle(req.Context(), query.From)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			respondWithJSON(w, http.StatusOK, response{Chirps: []Chirp{}, Users: []User{}})
+			return
+		}
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't get the user", err)
+			return
+		}
+		authorID = uuid.NullUUID{UUID: author.ID, Valid: true}
+	}
+
+	hashtag := sql.NullString{}
+	if query.Hashtag != "" {
+		hashtag = sql.NullString{String: query.Hashtag, Valid: true}
+	}
+
+	chirps, err := cfg.db.SearchChirps(req.Context(), database.SearchChirpsParams{
+		Query:    query.Text,
+		AuthorID: authorID,
+		Hashtag:  hashtag,
+		Limit:    int32(page.Limit + 1),
+		Offset:   int32(offset),
+		ViewerID: viewerID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't search chirps", err)
+		return
+	}
+
+	next := ""
+	if len(chirps) > page.Limit {
+		chirps = chirps[:page.Limit]
+		next = encodeCursor(pageCursor{Offset: offset + page.Limit})
+	}
+
+	chirpResponses, err := cfg.chirpResponses(req.Context(), viewerID, chirps)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't build the chirp response", err)
+		return
+	}
+
+	// A single bare word may be the start of someone's handle, so the first
+	// page also suggests matching users. Emails are neither matched nor
+	// shown, so search can't be used to find out who has an account.
+	userResponses := []User{}
+	if offset == 0 && query.Text != "" && !strings.ContainsAny(query.Text, ` "`) {
+		users, err := cfg.db.SearchUsers(req.Context(), database.SearchUsersParams{
+			Prefix: escapeLike(strings.ToLower(query.Text)),
+			Term:   strings.ToLower(query.Text),
+			Limit:  searchUsersLimit,
+		})
+		if err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't search users", err)
+			return
+		}
+		for _, user := range users {
+			userResponses = append(userResponses, User{
+				ID:          user.ID,
+				CreatedAt:   user.CreatedAt,
+				UpdatedAt:   user.UpdatedAt,
+				IsChirpyRed: user.IsChirpyRed,
+				Handle:      user.Handle.String,
+			})
+		}
+	}
+
+	setPaginationHeaders(w, req, next, "")
+	respondWithJSON(w, http.StatusOK, response{
+		Chirps: chirpResponses,
+		Users:  userResponses,
+	})
+}
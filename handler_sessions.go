@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// handlerGetSessions lists the caller's active sessions, most recently used
+// first.
+func (cfg *apiConfig) handlerGetSessions(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	tokens, err := cfg.db.ListSessions(req.Context(), userID)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get sessions", err)
+		return
+	}
+
+	response := make([]Session, len(tokens))
+	for i, token := range tokens {
+		response[i] = sessionFromDB(token)
+	}
+	respondWithJSON(w, http.StatusOK, response)
+}
+
+// handlerRevokeSession logs one session out. Its refresh token stops working
+// at once; access tokens already issued to it run until they expire.
+func (cfg *apiConfig) handlerRevokeSession(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	sessionID, err := uuid.Parse(req.PathValue("sessionID"))
+	if err != nil {
+		respondWithError(w, http.StatusBadRequest, "Couldn't parse the sessionID", err)
+		return
+	}
+
+	revoked, err := cfg.db.RevokeSession(req.Context(), database.RevokeSessionParams{
+		FamilyID: sessionID,
+		UserID:   userID,
+	})
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't revoke the session", err)
+		return
+	}
+	if revoked == 0 {
+		respondWithError(w, http.StatusNotFound, "Couldn't find the session", nil)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handlerRevokeAllSessions logs the caller out everywhere, including the
+// session making the request.
+func (cfg *apiConfig) handlerRevokeAllSessions(w http.ResponseWriter, req *http.Request) {
+	token, err := auth.GetBearerToken(req.Header)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't find JWT", err)
+		return
+	}
+
+	userID, err := cfg.jwtKeys().ValidateJWT(token)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	if err := cfg.db.RevokeAllSessions(req.Context(), userID); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't revoke sessions", err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
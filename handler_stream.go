@@ -0,0 +1,277 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	streamKeepAlive = 30 * time.Second
+	// streamReorderWindow is how far before the newest chirp sent a stream
+	// looks again when catching up. created_at is when a chirp's
+	// transaction started, so one can commit after a newer chirp and still
+	// sort before it.
+	streamReorderWindow = time.Minute
+)
+
+// chirpStreamFilter narrows a stream down to one author and/or hashtag.
+type chirpStreamFilter struct {
+	AuthorID uuid.NullUUID
+	Tag      sql.NullString
+}
+
+// handlerStreamChirps pushes new chirps to the client as server-sent events,
+// filtered by author_id and/or hashtag. Each event's id is a cursor, so a
+// reconnecting client that sends it back as Last-Event-ID (or last_event_id,
+// for clients that can't set headers) gets everything it missed first.
+// Catching up starts streamReorderWindow before that cursor, so chirps that
+// committed late aren't lost, and may repeat ones the client already has;
+// clients should skip chirp ids they've seen.
+func (cfg *apiConfig) handlerStreamChirps(w http.ResponseWriter, req *http.Request) {
+	viewerID, err := cfg.viewerID(req)
+	if err != nil {
+		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
+		return
+	}
+
+	filter := chirpStreamFilter{}
+	if authorIDString := req.URL.Query().Get("author_id"); authorIDString != "" {
+		id, err := uuid.Parse(authorIDString)
+		if err != nil {
+			respondWithError(w, http.StatusBadRequest, "Invalid author ID", err)
+			return
+		}
+		filter.AuthorID = uuid.NullUUID{UUID: id, Valid: true}
+	}
+	if tag := strings.TrimPrefix(req.URL.Query().Get("hashtag"), "#"); tag != "" {
+		filter.Tag = sql.NullString{String: tag, Valid: true}
+	}
+
+	// Without a Last-Event-ID the stream starts from now. Its cursor has no
+	// id, so it sorts before every chirp created at the same instant.
+	last := pageCursor{CreatedAt: time.Now().UTC()}
+	resuming := false
+	lastEventID := req.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = req.URL.Query().Get("last_event_id")
+	}
+	if lastEventID != "" {
+		c, err := decodeCursor(lastEventID)
+		if err != nil || c.Offset != 0 || c.Backward {
+			respondWithError(w, http.StatusBadRequest, "Invalid Last-Event-ID", err)
+			return
+		}
+		last = c
+		resuming = true
+	}
+
+	// Subscribe before catching up, so nothing created in between is lost.
+	sub := cfg.chirpStream.subscribe()
+	defer cfg.chirpStream.unsubscribe(sub)
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't open the stream", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		log.Printf("Couldn't open chirp stream: %v", err)
+		return
+	}
+
+	ctx := req.Context()
+	pos := newStreamPosition(last)
+	if resuming {
+		if err := cfg.catchUpChirpStream(ctx, w, rc, viewerID, filter, pos); err != nil {
+			logStreamError(ctx, err)
+			return
+		}
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case <-sub.wake:
+			ids, resync := sub.take()
+			var err error
+			if resync {
+				err = cfg.catchUpChirpStream(ctx, w, rc, viewerID, filter, pos)
+			} else if len(ids) > 0 {
+				err = cfg.sendNewChirps(ctx, w, rc, viewerID, filter, pos, ids)
+			}
+			if err != nil {
+				logStreamError(ctx, err)
+				return
+			}
+		}
+	}
+}
+
+// catchUpChirpStream sends every matching chirp created after the reorder
+// window before pos that hasn't been sent yet, in batches.
+func (cfg *apiConfig) catchUpChirpStream(ctx context.Context, w http.ResponseWriter, rc *http.ResponseController, viewerID uuid.UUID, filter chirpStreamFilter, pos *streamPosition) error {
+	after := pos.catchUpFrom()
+	for {
+		chirps, err := cfg.db.ListStreamChirpsAfter(ctx, database.ListStreamChirpsAfterParams{
+			AfterCreatedAt: after.CreatedAt,
+			AfterID:        after.ID,
+			AuthorID:       filter.AuthorID,
+			Tag:            filter.Tag,
+			ViewerID:       viewerID,
+			Limit:          maxPageLimit,
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := cfg.writeChirpEvents(ctx, w, rc, viewerID, pos, chirps); err != nil {
+			return err
+		}
+		if len(chirps) < maxPageLimit {
+			return nil
+		}
+		newest := chirps[len(chirps)-1]
+		after = pageCursor{CreatedAt: newest.CreatedAt, ID: newest.ID}
+	}
+}
+
+// sendNewChirps sends the chirps among ids that match the stream.
+func (cfg *apiConfig) sendNewChirps(ctx context.Context, w http.ResponseWriter, rc *http.ResponseController, viewerID uuid.UUID, filter chirpStreamFilter, pos *streamPosition, ids []uuid.UUID) error {
+	chirps, err := cfg.db.ListStreamChirpsByIDs(ctx, database.ListStreamChirpsByIDsParams{
+		Ids:      ids,
+		AuthorID: filter.AuthorID,
+		Tag:      filter.Tag,
+		ViewerID: viewerID,
+	})
+	if err != nil {
+		return err
+	}
+	return cfg.writeChirpEvents(ctx, w, rc, viewerID, pos, chirps)
+}
+
+// writeChirpEvents writes the chirps pos hasn't sent yet as "chirp" events.
+// Chirps the viewer muted, and rechirps of chirps hidden from them, are
+// dropped but still count as sent.
+func (cfg *apiConfig) writeChirpEvents(ctx context.Context, w http.ResponseWriter, rc *http.ResponseController, viewerID uuid.UUID, pos *streamPosition, chirps []database.Chirp) error {
+	fresh := pos.unsent(chirps)
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	response, err := cfg.chirpResponses(ctx, viewerID, fresh)
+	if err != nil {
+		return err
+	}
+	response = withoutHiddenRechirps(fresh, response)
+	response, err = cfg.withoutMutedKeywords(ctx, viewerID, response)
+	if err != nil {
+		return err
+	}
+
+	for _, chirp := range response {
+		dat, err := json.Marshal(chirp)
+		if err != nil {
+			return err
+		}
+		id := encodeCursor(pageCursor{CreatedAt: chirp.CreatedAt, ID: chirp.ID})
+		if _, err := fmt.Fprintf(w, "id: %s\nevent: chirp\ndata: %s\n\n", id, dat); err != nil {
+			return err
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		return err
+	}
+
+	pos.markSent(fresh)
+	return nil
+}
+
+// streamPosition tracks what a stream has sent. Chirps don't commit in
+// created_at order, so rather than only sending chirps after the newest one
+// sent, it remembers the ids sent within streamReorderWindow of it.
+type streamPosition struct {
+	last pageCursor
+	sent map[uuid.UUID]time.Time
+}
+
+func newStreamPosition(last pageCursor) *streamPosition {
+	return &streamPosition{last: last, sent: map[uuid.UUID]time.Time{}}
+}
+
+// unsent returns the chirps that haven't been sent yet.
+func (p *streamPosition) unsent(chirps []database.Chirp) []database.Chirp {
+	fresh := make([]database.Chirp, 0, len(chirps))
+	for _, chirp := range chirps {
+		if _, ok := p.sent[chirp.ID]; !ok {
+			fresh = append(fresh, chirp)
+		}
+	}
+	return fresh
+}
+
+// markSent records chirps as sent, moves last on to the newest chirp sent
+// so far, and forgets the ids that fell out of the reorder window.
+func (p *streamPosition) markSent(chirps []database.Chirp) {
+	for _, chirp := range chirps {
+		p.sent[chirp.ID] = chirp.CreatedAt
+		if cursorAfter(chirp.CreatedAt, chirp.ID, p.last) {
+			p.last = pageCursor{CreatedAt: chirp.CreatedAt, ID: chirp.ID}
+		}
+	}
+
+	horizon := p.catchUpFrom().CreatedAt
+	for id, createdAt := range p.sent {
+		if createdAt.Before(horizon) {
+			delete(p.sent, id)
+		}
+	}
+}
+
+// catchUpFrom is where catching up starts: streamReorderWindow before the
+// newest chirp sent.
+func (p *streamPosition) catchUpFrom() pageCursor {
+	return pageCursor{CreatedAt: p.last.CreatedAt.Add(-streamReorderWindow)}
+}
+
+// cursorAfter reports whether (createdAt, id) sorts after c, the way the
+// stream queries order chirps.
+func cursorAfter(createdAt time.Time, id uuid.UUID, c pageCursor) bool {
+	if !createdAt.Equal(c.CreatedAt) {
+		return createdAt.After(c.CreatedAt)
+	}
+	return bytes.Compare(id[:], c.ID[:]) > 0
+}
+
+// logStreamError logs why a stream ended, unless the client just went away.
+func logStreamError(ctx context.Context, err error) {
+	if ctx.Err() == nil {
+		log.Printf("Chirp stream closed: %v", err)
+	}
+}
@@ -1,21 +1,28 @@
 package main
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"regexp"
 	"time"
 
 	"github.com/Tanay-Verma/chirpy/internal/auth"
 	"github.com/Tanay-Verma/chirpy/internal/database"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+var handlePattern = regexp.MustCompile(`^[A-Za-z0-9_]{1,30}$`)
+
 type User struct {
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
-	Email       string    `json:"email"`
+	Email       string    `json:"email,omitempty"`
 	ID          uuid.UUID `json:"id"`
 	IsChirpyRed bool      `json:"is_chirpy_red"`
+	Handle      string    `json:"handle,omitempty"`
 }
 
 func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, req *http.Request) {
@@ -52,6 +59,7 @@ func (cfg *apiConfig) handlerCreateUser(w http.ResponseWriter, req *http.Request
 		UpdatedAt:   newUser.UpdatedAt,
 		Email:       newUser.Email,
 		IsChirpyRed: newUser.IsChirpyRed,
+		Handle:      newUser.Handle.String,
 	})
 }
 
@@ -59,6 +67,7 @@ func (cfg *apiConfig) handlerUpdateUser(w http.ResponseWriter, req *http.Request
 	type parameters struct {
 		Email    string `json:"email"`
 		Password string `json:"password"`
+		Handle   string `json:"handle"`
 	}
 	type response struct {
 		User
@@ -70,7 +79,7 @@ func (cfg *apiConfig) handlerUpdateUser(w http.ResponseWriter, req *http.Request
 		return
 	}
 
-	user_id, err := auth.ValidateJWT(accessToken, cfg.jwtSecret)
+	user_id, err := cfg.jwtKeys().ValidateJWT(accessToken)
 	if err != nil {
 		respondWithError(w, http.StatusUnauthorized, "Couldn't validate JWT", err)
 		return
@@ -83,13 +92,33 @@ func (cfg *apiConfig) handlerUpdateUser(w http.ResponseWriter, req *http.Request
 		return
 	}
 
+	if params.Handle != "" && !handlePattern.MatchString(params.Handle) {
+		respondWithError(w, http.StatusBadRequest, "Handles are 1-30 letters, digits or underscores", nil)
+		return
+	}
+
+	currentUser, err := cfg.db.GetUserByID(req.Context(), user_id)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't get the user", err)
+		return
+	}
+	passwordChanged := auth.CheckPasswordHash(params.Password, currentUser.HashedPassword) != nil
+
 	hashedPassword, err := auth.HashPassword(params.Password)
 	if err != nil {
 		respondWithError(w, http.StatusUnauthorized, "Couldn't generate hashed password", err)
 		return
 	}
 
-	updatedUser, err := cfg.db.UpdateUser(req.Context(), database.UpdateUserParams{
+	tx, err := cfg.dbConn.BeginTx(req.Context(), nil)
+	if err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update the user", err)
+		return
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	updatedUser, err := qtx.UpdateUser(req.Context(), database.UpdateUserParams{
 		ID:             user_id,
 		Email:          params.Email,
 		HashedPassword: hashedPassword,
@@ -99,6 +128,37 @@ func (cfg *apiConfig) handlerUpdateUser(w http.ResponseWriter, req *http.Request
 		return
 	}
 
+	// A new password logs every session out, in case the old one leaked.
+	// Both happen in one transaction, so the password never changes while
+	// the old sessions live on.
+	if passwordChanged {
+		if err := qtx.RevokeAllSessions(req.Context(), user_id); err != nil {
+			respondWithError(w, http.StatusInternalServerError, "Couldn't revoke sessions", err)
+			return
+		}
+	}
+
+	if params.Handle != "" && params.Handle != updatedUser.Handle.String {
+		updatedUser, err = qtx.UpdateUserHandle(req.Context(), database.UpdateUserHandleParams{
+			ID:     user_id,
+			Handle: sql.NullString{String: params.Handle, Valid: true},
+		})
+		if err != nil {
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				respondWithError(w, http.StatusConflict, "That handle is already taken", err)
+				return
+			}
+			respondWithError(w, http.StatusInternalServerError, "Couldn't update the handle", err)
+			return
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		respondWithError(w, http.StatusInternalServerError, "Couldn't update the user", err)
+		return
+	}
+
 	respondWithJSON(w, http.StatusOK, response{
 		User: User{
 			ID:          updatedUser.ID,
@@ -106,6 +166,7 @@ func (cfg *apiConfig) handlerUpdateUser(w http.ResponseWriter, req *http.Request
 			UpdatedAt:   updatedUser.UpdatedAt,
 			Email:       updatedUser.Email,
 			IsChirpyRed: updatedUser.IsChirpyRed,
+			Handle:      updatedUser.Handle.String,
 		},
 	})
 }
@@ -6,10 +6,18 @@ import (
 	"time"
 
 	"github.com/Tanay-Verma/chirpy/internal/auth"
-	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
 )
 
-const expiresIn = time.Hour
+const (
+	expiresIn = time.Hour
+	// refreshTokenExpiresIn restarts with every refresh, so a session only
+	// ends after going this long unused.
+	refreshTokenExpiresIn = 60 * 24 * time.Hour
+	// refreshReuseGrace is how long after a refresh the same refresh token
+	// can be sent again to get the same successor.
+	refreshReuseGrace = 30 * time.Second
+)
 
 func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, req *http.Request) {
 	type parameters struct {
@@ -39,24 +47,13 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, req *http.Request) {
 		respondWithError(w, http.StatusUnauthorized, "Password does not match please try again...", err)
 	}
 
-	jwtToken, err := auth.MakeJWT(user.ID, cfg.jwtSecret, expiresIn)
+	jwtToken, err := cfg.jwtKeys().MakeJWT(user.ID, expiresIn)
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't generate JWT token", err)
 		return
 	}
 
-	refreshToken, err := auth.MakeRefreshToken()
-	if err != nil {
-		respondWithError(w, http.StatusInternalServerError, "Couldn't generate refresh token", err)
-		return
-	}
-
-	const refresh_token_expiration_duration = 60 * 24 * time.Hour
-	newRefreshToken, err := cfg.db.CreateRefreshToken(req.Context(), database.CreateRefreshTokenParams{
-		Token:     refreshToken,
-		UserID:    user.ID,
-		ExpiresAt: time.Now().Add(refresh_token_expiration_duration),
-	})
+	refreshToken, err := issueRefreshToken(req.Context(), cfg.db, user.ID, uuid.New(), time.Now().UTC(), sessionClientFromRequest(req))
 	if err != nil {
 		respondWithError(w, http.StatusInternalServerError, "Couldn't save refresh token", err)
 		return
@@ -69,8 +66,9 @@ func (cfg *apiConfig) handlerLogin(w http.ResponseWriter, req *http.Request) {
 			UpdatedAt:   user.UpdatedAt,
 			Email:       user.Email,
 			IsChirpyRed: user.IsChirpyRed,
+			Handle:      user.Handle.String,
 		},
 		Token:        jwtToken,
-		RefreshToken: newRefreshToken.Token,
+		RefreshToken: refreshToken,
 	})
 }
@@ -1,15 +1,14 @@
 package auth
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"net/http"
 	"strings"
-	"time"
 
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -21,6 +20,10 @@ const (
 
 var ErrNoAuthHeaderIncluded = errors.New("no auth header included in request")
 
+// refreshTokenLookupLength is how much of a refresh token is stored as is,
+// to find its row; the rest only ever exists as part of a hash.
+const refreshTokenLookupLength = 16
+
 func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
@@ -33,49 +36,6 @@ func CheckPasswordHash(password, hash string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 }
 
-func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
-		Issuer:    string(TokenTypeAccess),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
-		Subject:   userID.String(),
-	})
-
-	return token.SignedString([]byte(tokenSecret))
-}
-
-func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
-	token, err := jwt.ParseWithClaims(
-		tokenString,
-		&jwt.RegisteredClaims{},
-		func(t *jwt.Token) (interface{}, error) {
-			return []byte(tokenSecret), nil
-		})
-	if err != nil {
-		return uuid.UUID{}, err
-	}
-
-	userIDString, err := token.Claims.GetSubject()
-	if err != nil {
-		return uuid.UUID{}, err
-	}
-
-	issuer, err := token.Claims.GetIssuer()
-	if err != nil {
-		return uuid.Nil, err
-	}
-	if issuer != string(TokenTypeAccess) {
-		return uuid.Nil, errors.New("invalid issuer")
-	}
-
-	userID, err := uuid.Parse(userIDString)
-	if err != nil {
-		return uuid.UUID{}, err
-	}
-
-	return userID, nil
-}
-
 func GetBearerToken(headers http.Header) (string, error) {
 	authHeader := headers.Get("Authorization")
 	if authHeader == "" {
@@ -113,3 +73,27 @@ func MakeRefreshToken() (string, error) {
 
 	return hex.EncodeToString(randomBytes), nil
 }
+
+// NextRefreshToken derives the refresh token that replaces token when it's
+// rotated. Deriving it rather than drawing a random one lets a retry of the
+// same refresh be handed the same successor, and without secret nobody can
+// work out what it will be.
+func NextRefreshToken(token, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("chirpy-refresh-token:" + token))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseRefreshToken splits a refresh token into the lookup id its row is
+// stored under and the SHA-256 hash stored in place of the token itself.
+func ParseRefreshToken(token string) (lookupID, hash string, err error) {
+	if len(token) != 64 {
+		return "", "", errors.New("malformed refresh token")
+	}
+	if _, err := hex.DecodeString(token); err != nil {
+		return "", "", errors.New("malformed refresh token")
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	return token[:refreshTokenLookupLength], hex.EncodeToString(sum[:]), nil
+}
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -62,43 +63,43 @@ func TestCheckPasswordHash(t *testing.T) {
 }
 
 func TestValidateJWT(t *testing.T) {
-	tokenSecret := "SECRET"
-	wrongSecret := "WRONG_SECRET"
+	keyring := testKeyring(t, "current", AlgorithmEdDSA)
+	wrongKeyring := testKeyring(t, "current", AlgorithmEdDSA)
 	userID := uuid.New()
-	validToken, _ := MakeJWT(userID, tokenSecret, time.Hour)
-	expiredToken, _ := MakeJWT(userID, tokenSecret, -1*time.Hour)
+	validToken, _ := keyring.MakeJWT(userID, time.Hour)
+	expiredToken, _ := keyring.MakeJWT(userID, -1*time.Hour)
 	tests := []struct {
 		name        string
 		tokenString string
-		tokenSecret string
+		keyring     *Keyring
 		wantUserID  uuid.UUID
 		wantErr     bool
 	}{
 		{
 			name:        "Valid Token",
 			tokenString: validToken,
-			tokenSecret: tokenSecret,
+			keyring:     keyring,
 			wantUserID:  userID,
 			wantErr:     false,
 		},
 		{
 			name:        "Expired Token",
 			tokenString: expiredToken,
-			tokenSecret: tokenSecret,
+			keyring:     keyring,
 			wantUserID:  uuid.Nil,
 			wantErr:     true,
 		},
 		{
 			name:        "Invalid Token",
 			tokenString: "invalid.token.string",
-			tokenSecret: validToken,
+			keyring:     keyring,
 			wantUserID:  uuid.Nil,
 			wantErr:     true,
 		},
 		{
-			name:        "Wrong Secret",
+			name:        "Wrong Key",
 			tokenString: validToken,
-			tokenSecret: wrongSecret,
+			keyring:     wrongKeyring,
 			wantUserID:  uuid.Nil,
 			wantErr:     true,
 		},
@@ -106,7 +107,7 @@ func TestValidateJWT(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotUserID, err := ValidateJWT(tt.tokenString, tt.tokenSecret)
+			gotUserID, err := tt.keyring.ValidateJWT(tt.tokenString)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ValidateJWT() error = %v, wantErr = %v", err, tt.wantErr)
@@ -164,3 +165,65 @@ func TestGetBearerToken(t *testing.T) {
 		})
 	}
 }
+
+func TestParseRefreshToken(t *testing.T) {
+	token, err := MakeRefreshToken()
+	if err != nil {
+		t.Fatalf("MakeRefreshToken() error = %v", err)
+	}
+	other, err := MakeRefreshToken()
+	if err != nil {
+		t.Fatalf("MakeRefreshToken() error = %v", err)
+	}
+
+	lookupID, hash, err := ParseRefreshToken(token)
+	if err != nil {
+		t.Fatalf("ParseRefreshToken() error = %v", err)
+	}
+	if lookupID != token[:16] {
+		t.Errorf("ParseRefreshToken() lookupID = %v, want %v", lookupID, token[:16])
+	}
+	if len(hash) != 64 || strings.Contains(hash, token) {
+		t.Errorf("ParseRefreshToken() hash = %v, want a hex SHA-256 that isn't the token", hash)
+	}
+
+	_, sameHash, _ := ParseRefreshToken(token)
+	_, otherHash, _ := ParseRefreshToken(other)
+	if sameHash != hash || otherHash == hash {
+		t.Errorf("ParseRefreshToken() hashes aren't stable and distinct")
+	}
+
+	// The hash has to match what the migration computed in SQL for tokens
+	// that were stored raw: encode(sha256(token), 'hex').
+	_, known, _ := ParseRefreshToken(strings.Repeat("0", 64))
+	if want := "60e05bd1b195af2f94112fa7197a5c88289058840ce7c6df9693756bc6250f55"; known != want {
+		t.Errorf("ParseRefreshToken() hash = %v, want %v", known, want)
+	}
+
+	for _, bad := range []string{"", token[:63], token + "0", strings.Repeat("z", 64)} {
+		if _, _, err := ParseRefreshToken(bad); err == nil {
+			t.Errorf("ParseRefreshToken(%q) expected an error", bad)
+		}
+	}
+}
+
+func TestNextRefreshToken(t *testing.T) {
+	token, err := MakeRefreshToken()
+	if err != nil {
+		t.Fatalf("MakeRefreshToken() error = %v", err)
+	}
+
+	next := NextRefreshToken(token, "secret")
+	if _, _, err := ParseRefreshToken(next); err != nil {
+		t.Errorf("NextRefreshToken() = %v, not a valid refresh token: %v", next, err)
+	}
+	if next == token {
+		t.Error("NextRefreshToken() returned the token it was given")
+	}
+	if again := NextRefreshToken(token, "secret"); again != next {
+		t.Errorf("NextRefreshToken() = %v then %v, want the same successor", next, again)
+	}
+	if NextRefreshToken(token, "other secret") == next {
+		t.Error("NextRefreshToken() doesn't depend on the secret")
+	}
+}
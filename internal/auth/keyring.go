@@ -0,0 +1,288 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+
+	rsaKeyBits = 2048
+)
+
+var ErrNoSigningKey = errors.New("no active signing key")
+
+// SigningKey is one key pair in a Keyring. It signs tokens from ActivatesAt
+// until a newer key activates, and verifies them until RetiresAt; a zero
+// RetiresAt means it hasn't been superseded yet.
+type SigningKey struct {
+	ID          string
+	Algorithm   string
+	ActivatesAt time.Time
+	RetiresAt   time.Time
+	private     crypto.Signer
+}
+
+// NewSigningKey wraps a private key made by GenerateSigningKey or
+// DecryptPrivateKey, checking that it suits algorithm.
+func NewSigningKey(id, algorithm string, private crypto.Signer, activatesAt, retiresAt time.Time) (*SigningKey, error) {
+	switch private.(type) {
+	case ed25519.PrivateKey:
+		if algorithm != AlgorithmEdDSA {
+			return nil, fmt.Errorf("an Ed25519 key can't be used for %s", algorithm)
+		}
+	case *rsa.PrivateKey:
+		if algorithm != AlgorithmRS256 {
+			return nil, fmt.Errorf("an RSA key can't be used for %s", algorithm)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", private)
+	}
+
+	return &SigningKey{
+		ID:          id,
+		Algorithm:   algorithm,
+		ActivatesAt: activatesAt,
+		RetiresAt:   retiresAt,
+		private:     private,
+	}, nil
+}
+
+func (k *SigningKey) method() jwt.SigningMethod {
+	if k.Algorithm == AlgorithmRS256 {
+		return jwt.SigningMethodRS256
+	}
+	return jwt.SigningMethodEdDSA
+}
+
+func (k *SigningKey) verifies(now time.Time) bool {
+	return k.RetiresAt.IsZero() || now.Before(k.RetiresAt)
+}
+
+// GenerateSigningKey makes a new private key for algorithm.
+func GenerateSigningKey(algorithm string) (crypto.Signer, error) {
+	switch algorithm {
+	case AlgorithmEdDSA:
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		return private, err
+	case AlgorithmRS256:
+		return rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+}
+
+// EncryptPrivateKey seals a private key with AES-GCM under a key derived
+// from secret, so the stored copy is useless without the server's secret.
+func EncryptPrivateKey(private crypto.Signer, secret string) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return "", err
+	}
+
+	gcm, err := keyEncryptionCipher(secret)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, der, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// DecryptPrivateKey reverses EncryptPrivateKey.
+func DecryptPrivateKey(data, secret string) (crypto.Signer, error) {
+	sealed, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := keyEncryptionCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("encrypted key is too short")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	der, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	private, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := private.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported key type %T", private)
+	}
+	return signer, nil
+}
+
+func keyEncryptionCipher(secret string) (cipher.AEAD, error) {
+	key := sha256.Sum256([]byte("chirpy-jwt-keys:" + secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Keyring signs access tokens with its newest active key and verifies them
+// with any key that hasn't retired. Tokens name their key in the kid header.
+type Keyring struct {
+	keys []*SigningKey
+}
+
+func NewKeyring(keys ...*SigningKey) *Keyring {
+	return &Keyring{keys: keys}
+}
+
+func (k *Keyring) signingKey(now time.Time) *SigningKey {
+	var current *SigningKey
+	for _, key := range k.keys {
+		if key.ActivatesAt.After(now) || !key.verifies(now) {
+			continue
+		}
+		if current == nil || key.ActivatesAt.After(current.ActivatesAt) {
+			current = key
+		}
+	}
+	return current
+}
+
+// CanSign reports whether the keyring has a key that can sign right now.
+func (k *Keyring) CanSign() bool {
+	return k.signingKey(time.Now()) != nil
+}
+
+func (k *Keyring) MakeJWT(userID uuid.UUID, expiresIn time.Duration) (string, error) {
+	now := time.Now()
+	key := k.signingKey(now)
+	if key == nil {
+		return "", ErrNoSigningKey
+	}
+
+	token := jwt.NewWithClaims(key.method(), jwt.RegisteredClaims{
+		Issuer:    string(TokenTypeAccess),
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(expiresIn)),
+		Subject:   userID.String(),
+	})
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.private)
+}
+
+func (k *Keyring) ValidateJWT(tokenString string) (uuid.UUID, error) {
+	token, err := jwt.ParseWithClaims(
+		tokenString,
+		&jwt.RegisteredClaims{},
+		func(t *jwt.Token) (interface{}, error) {
+			kid, _ := t.Header["kid"].(string)
+			for _, key := range k.keys {
+				if key.ID != kid || !key.verifies(time.Now()) {
+					continue
+				}
+				// A token can't pick a different algorithm than its key's.
+				if t.Method.Alg() != key.Algorithm {
+					return nil, errors.New("signing method doesn't match the key")
+				}
+				return key.private.Public(), nil
+			}
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		},
+		jwt.WithValidMethods([]string{AlgorithmEdDSA, AlgorithmRS256}),
+	)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	userIDString, err := token.Claims.GetSubject()
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	issuer, err := token.Claims.GetIssuer()
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if issuer != string(TokenTypeAccess) {
+		return uuid.Nil, errors.New("invalid issuer")
+	}
+
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return userID, nil
+}
+
+// JWK is the public half of a signing key, as published in a JWK Set
+// (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS lists the public keys other services need to verify tokens: every
+// key that hasn't retired, including ones not signing yet, so they're
+// already cached by the time they start to.
+func (k *Keyring) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	now := time.Now()
+	for _, key := range k.keys {
+		if !key.verifies(now) {
+			continue
+		}
+
+		jwk := JWK{KeyID: key.ID, Use: "sig", Algorithm: key.Algorithm}
+		switch public := key.private.Public().(type) {
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		default:
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
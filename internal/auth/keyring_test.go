@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func testSigningKey(t *testing.T, id, algorithm string, activatesAt, retiresAt time.Time) *SigningKey {
+	t.Helper()
+	private, err := GenerateSigningKey(algorithm)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	key, err := NewSigningKey(id, algorithm, private, activatesAt, retiresAt)
+	if err != nil {
+		t.Fatalf("NewSigningKey() error = %v", err)
+	}
+	return key
+}
+
+func testKeyring(t *testing.T, id, algorithm string) *Keyring {
+	t.Helper()
+	return NewKeyring(testSigningKey(t, id, algorithm, time.Now().Add(-time.Hour), time.Time{}))
+}
+
+func TestKeyringAlgorithms(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmEdDSA, AlgorithmRS256} {
+		t.Run(algorithm, func(t *testing.T) {
+			keyring := testKeyring(t, "key-1", algorithm)
+			userID := uuid.New()
+
+			token, err := keyring.MakeJWT(userID, time.Hour)
+			if err != nil {
+				t.Fatalf("MakeJWT() error = %v", err)
+			}
+
+			parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+			if err != nil {
+				t.Fatalf("ParseUnverified() error = %v", err)
+			}
+			if parsed.Header["kid"] != "key-1" || parsed.Header["alg"] != algorithm {
+				t.Errorf("header = %v, want kid key-1 and alg %s", parsed.Header, algorithm)
+			}
+
+			got, err := keyring.ValidateJWT(token)
+			if err != nil || got != userID {
+				t.Errorf("ValidateJWT() = %v, %v, want %v", got, err, userID)
+			}
+		})
+	}
+}
+
+func TestKeyringRotation(t *testing.T) {
+	now := time.Now()
+	old := testSigningKey(t, "old", AlgorithmEdDSA, now.Add(-48*time.Hour), now.Add(time.Hour))
+	current := testSigningKey(t, "current", AlgorithmRS256, now.Add(-time.Hour), time.Time{})
+	pending := testSigningKey(t, "pending", AlgorithmEdDSA, now.Add(24*time.Hour), time.Time{})
+	retired := testSigningKey(t, "retired", AlgorithmEdDSA, now.Add(-96*time.Hour), now.Add(-time.Hour))
+	userID := uuid.New()
+
+	oldToken, err := NewKeyring(old).MakeJWT(userID, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+	retiredToken, err := NewKeyring(testSigningKey(t, "retired", AlgorithmEdDSA, now.Add(-time.Hour), time.Time{})).MakeJWT(userID, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+
+	keyring := NewKeyring(old, current, pending, retired)
+
+	token, err := keyring.MakeJWT(userID, time.Hour)
+	if err != nil {
+		t.Fatalf("MakeJWT() error = %v", err)
+	}
+	parsed, _, _ := jwt.NewParser().ParseUnverified(token, &jwt.RegisteredClaims{})
+	if parsed.Header["kid"] != "current" {
+		t.Errorf("signed with %v, want the newest active key", parsed.Header["kid"])
+	}
+
+	if _, err := keyring.ValidateJWT(oldToken); err != nil {
+		t.Errorf("a token from a superseded key should still verify: %v", err)
+	}
+	if _, err := keyring.ValidateJWT(retiredToken); err == nil {
+		t.Error("a token from a retired key shouldn't verify")
+	}
+
+	jwks := keyring.JWKS()
+	kids := map[string]string{}
+	for _, key := range jwks.Keys {
+		kids[key.KeyID] = key.KeyType
+	}
+	want := map[string]string{"old": "OKP", "current": "RSA", "pending": "OKP"}
+	if len(kids) != len(want) {
+		t.Errorf("JWKS() keys = %v, want %v", kids, want)
+	}
+	for kid, kty := range want {
+		if kids[kid] != kty {
+			t.Errorf("JWKS() key %s = %q, want %q", kid, kids[kid], kty)
+		}
+	}
+
+	if _, err := NewKeyring(pending).MakeJWT(userID, time.Hour); err != ErrNoSigningKey {
+		t.Errorf("MakeJWT() with only a pending key error = %v, want ErrNoSigningKey", err)
+	}
+
+	if !keyring.CanSign() {
+		t.Error("CanSign() = false, want true with an active key")
+	}
+	if NewKeyring(pending, retired).CanSign() {
+		t.Error("CanSign() = true, want false with only pending and retired keys")
+	}
+}
+
+func TestKeyringRejectsAlgorithmSwitch(t *testing.T) {
+	keyring := testKeyring(t, "key-1", AlgorithmRS256)
+
+	// An HS256 token signed with the public key as its secret must not pass
+	// as one signed by the RSA key.
+	public := keyring.keys[0].private.Public().(*rsa.PublicKey)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    string(TokenTypeAccess),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		Subject:   uuid.NewString(),
+	})
+	token.Header["kid"] = "key-1"
+	signed, err := token.SignedString(public.N.Bytes())
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	if _, err := keyring.ValidateJWT(signed); err == nil {
+		t.Error("ValidateJWT() accepted an HS256 token")
+	}
+}
+
+func TestEncryptPrivateKey(t *testing.T) {
+	for _, algorithm := range []string{AlgorithmEdDSA, AlgorithmRS256} {
+		t.Run(algorithm, func(t *testing.T) {
+			private, err := GenerateSigningKey(algorithm)
+			if err != nil {
+				t.Fatalf("GenerateSigningKey() error = %v", err)
+			}
+
+			sealed, err := EncryptPrivateKey(private, "secret")
+			if err != nil {
+				t.Fatalf("EncryptPrivateKey() error = %v", err)
+			}
+
+			opened, err := DecryptPrivateKey(sealed, "secret")
+			if err != nil {
+				t.Fatalf("DecryptPrivateKey() error = %v", err)
+			}
+			switch key := private.(type) {
+			case ed25519.PrivateKey:
+				if !key.Equal(opened) {
+					t.Error("DecryptPrivateKey() returned a different key")
+				}
+			case *rsa.PrivateKey:
+				if !key.Equal(opened) {
+					t.Error("DecryptPrivateKey() returned a different key")
+				}
+			}
+
+			if _, err := DecryptPrivateKey(sealed, "wrong secret"); err == nil {
+				t.Error("DecryptPrivateKey() with the wrong secret should fail")
+			}
+		})
+	}
+}
+
+func TestNewSigningKeyChecksAlgorithm(t *testing.T) {
+	private, err := GenerateSigningKey(AlgorithmEdDSA)
+	if err != nil {
+		t.Fatalf("GenerateSigningKey() error = %v", err)
+	}
+	if _, err := NewSigningKey("key-1", AlgorithmRS256, private, time.Now(), time.Time{}); err == nil {
+		t.Error("NewSigningKey() accepted an Ed25519 key for RS256")
+	}
+	if _, err := GenerateSigningKey("HS256"); err == nil {
+		t.Error("GenerateSigningKey() accepted HS256")
+	}
+}
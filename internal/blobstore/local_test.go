@@ -0,0 +1,51 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+
+	if err := store.Put(ctx, "a.png", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	r, err := store.Open(ctx, "a.png")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	dat, _ := io.ReadAll(r)
+	r.Close()
+	if string(dat) != "hello" {
+		t.Errorf("got %q, want %q", dat, "hello")
+	}
+
+	if err := store.Delete(ctx, "a.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Open(ctx, "a.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalRejectsBadKeys(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocal: %v", err)
+	}
+
+	for _, key := range []string{"", "../etc/passwd", "a/b.png", `a\b.png`, ".upload-1"} {
+		if err := store.Put(context.Background(), key, strings.NewReader("x")); err == nil {
+			t.Errorf("Put(%q) succeeded, want error", key)
+		}
+	}
+}
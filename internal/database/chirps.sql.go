@@ -7,34 +7,109 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const adjustLikeCount = `-- name: AdjustLikeCount :exec
+UPDATE chirps
+SET like_count = GREATEST(like_count + $1::integer, 0)
+WHERE id = $2
+`
+
+type AdjustLikeCountParams struct {
+	Delta int32
+	ID    uuid.UUID
+}
+
+func (q *Queries) AdjustLikeCount(ctx context.Context, arg AdjustLikeCountParams) error {
+	_, err := q.db.ExecContext(ctx, adjustLikeCount, arg.Delta, arg.ID)
+	return err
+}
+
+const adjustReactionCount = `-- name: AdjustReactionCount :exec
+UPDATE chirps
+SET reaction_counts = CASE
+  WHEN COALESCE((reaction_counts ->> $1::text)::integer, 0) + $2::integer <= 0
+    THEN reaction_counts - $1::text
+  ELSE jsonb_set(
+    reaction_counts,
+    ARRAY[$1::text],
+    to_jsonb(COALESCE((reaction_counts ->> $1::text)::integer, 0) + $2::integer)
+  )
+END
+WHERE id = $3
+`
+
+type AdjustReactionCountParams struct {
+	Emoji string
+	Delta int32
+	ID    uuid.UUID
+}
+
+func (q *Queries) AdjustReactionCount(ctx context.Context, arg AdjustReactionCountParams) error {
+	_, err := q.db.ExecContext(ctx, adjustReactionCount, arg.Emoji, arg.Delta, arg.ID)
+	return err
+}
+
 const createChirp = `-- name: CreateChirp :one
 INSERT INTO chirps (
   id,
   created_at,
   updated_at,
   body,
-  user_id
+  user_id,
+  in_reply_to_id,
+  root_id,
+  rechirp_of_id,
+  quote_of_id,
+  visibility,
+  content_warning,
+  expires_at
 ) VALUES (
   gen_random_uuid(),
   NOW(),
   NOW(),
   $1,
-  $2
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9
 )
-RETURNING id, created_at, updated_at, body, user_id
+RETURNING id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
 `
 
 type CreateChirpParams struct {
-	Body   string
-	UserID uuid.UUID
+	Body           string
+	UserID         uuid.UUID
+	InReplyToID    uuid.NullUUID
+	RootID         uuid.NullUUID
+	RechirpOfID    uuid.NullUUID
+	QuoteOfID      uuid.NullUUID
+	Visibility     string
+	ContentWarning sql.NullString
+	ExpiresAt      sql.NullTime
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, createChirp, arg.Body, arg.UserID)
+	row := q.db.QueryRowContext(ctx, createChirp,
+		arg.Body,
+		arg.UserID,
+		arg.InReplyToID,
+		arg.RootID,
+		arg.RechirpOfID,
+		arg.QuoteOfID,
+		arg.Visibility,
+		arg.ContentWarning,
+		arg.ExpiresAt,
+	)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -42,6 +117,16 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.InReplyToID,
+		&i.RootID,
+		&i.DeletedAt,
+		&i.LikeCount,
+		&i.ReactionCounts,
+		&i.RechirpOfID,
+		&i.QuoteOfID,
+		&i.Visibility,
+		&i.ContentWarning,
+		&i.ExpiresAt,
 	)
 	return i, err
 }
@@ -62,13 +147,21 @@ func (q *Queries) DeleteChirp(ctx context.Context, arg DeleteChirpParams) error
 }
 
 const getChirp = `-- name: GetChirp :one
-SELECT id, created_at, updated_at, body, user_id
+SELECT id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
 FROM chirps
 WHERE id = $1
+AND deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
 `
 
-func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
-	row := q.db.QueryRowContext(ctx, getChirp, id)
+type GetChirpParams struct {
+	ID       uuid.UUID
+	ViewerID uuid.UUID
+}
+
+func (q *Queries) GetChirp(ctx context.Context, arg GetChirpParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirp, arg.ID, arg.ViewerID)
 	var i Chirp
 	err := row.Scan(
 		&i.ID,
@@ -76,18 +169,228 @@ func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.InReplyToID,
+		&i.RootID,
+		&i.DeletedAt,
+		&i.LikeCount,
+		&i.ReactionCounts,
+		&i.RechirpOfID,
+		&i.QuoteOfID,
+		&i.Visibility,
+		&i.ContentWarning,
+		&i.ExpiresAt,
 	)
 	return i, err
 }
 
-const getChirps = `-- name: GetChirps :many
-SELECT id, created_at, updated_at, body, user_id
+const getChirpForUpdate = `-- name: GetChirpForUpdate :one
+SELECT id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
+FROM chirps
+WHERE id = $1 AND deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+FOR UPDATE
+`
+
+func (q *Queries) GetChirpForUpdate(ctx context.Context, id uuid.UUID) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, getChirpForUpdate, id)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.InReplyToID,
+		&i.RootID,
+		&i.DeletedAt,
+		&i.LikeCount,
+		&i.ReactionCounts,
+		&i.RechirpOfID,
+		&i.QuoteOfID,
+		&i.Visibility,
+		&i.ContentWarning,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const hasReplies = `-- name: HasReplies :one
+SELECT EXISTS (
+  SELECT 1
+  FROM chirps
+  WHERE in_reply_to_id = $1::uuid
+) AS has_replies
+`
+
+func (q *Queries) HasReplies(ctx context.Context, chirpID uuid.UUID) (bool, error) {
+	row := q.db.QueryRowContext(ctx, hasReplies, chirpID)
+	var hasReplies bool
+	err := row.Scan(&hasReplies)
+	return hasReplies, err
+}
+
+const listChirpsAsc = `-- name: ListChirpsAsc :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
+FROM chirps
+WHERE deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND ($1::uuid IS NULL OR user_id = $1)
+AND ($1::uuid IS NOT NULL OR visibility <> 'unlisted')
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
+AND NOT chirp_hidden_from(user_id, $2::uuid)
+AND (
+  $3::timestamp IS NULL
+  OR (created_at, id) > ($3, $4::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT $5
+`
+
+type ListChirpsAscParams struct {
+	AuthorID       uuid.NullUUID
+	ViewerID       uuid.UUID
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	Limit          int32
+}
+
+func (q *Queries) ListChirpsAsc(ctx context.Context, arg ListChirpsAscParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsAsc,
+		arg.AuthorID,
+		arg.ViewerID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyToID,
+			&i.RootID,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.ReactionCounts,
+			&i.RechirpOfID,
+			&i.QuoteOfID,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsByHashtag = `-- name: ListChirpsByHashtag :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
+FROM chirps
+WHERE deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND EXISTS (
+  SELECT 1
+  FROM chirp_entities
+  WHERE chirp_entities.chirp_id = chirps.id
+  AND chirp_entities.kind = 'hashtag'
+  AND lower(chirp_entities.value) = lower($1::text)
+)
+AND visibility <> 'unlisted'
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
+AND NOT chirp_hidden_from(user_id, $2::uuid)
+AND (
+  $3::timestamp IS NULL
+  OR (created_at, id) < ($3, $4::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type ListChirpsByHashtagParams struct {
+	Tag             string
+	ViewerID        uuid.UUID
+	BeforeCreatedAt sql.NullTime
+	BeforeID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) ListChirpsByHashtag(ctx context.Context, arg ListChirpsByHashtagParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsByHashtag,
+		arg.Tag,
+		arg.ViewerID,
+		arg.BeforeCreatedAt,
+		arg.BeforeID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyToID,
+			&i.RootID,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.ReactionCounts,
+			&i.RechirpOfID,
+			&i.QuoteOfID,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsByIDs = `-- name: ListChirpsByIDs :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
 FROM chirps
-ORDER BY created_at ASC
+WHERE id = ANY($1::uuid[])
+AND (expires_at IS NULL OR expires_at > NOW())
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
+AND NOT chirp_hidden_from(user_id, $2::uuid)
 `
 
-func (q *Queries) GetChirps(ctx context.Context) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getChirps)
+type ListChirpsByIDsParams struct {
+	Ids      []uuid.UUID
+	ViewerID uuid.UUID
+}
+
+func (q *Queries) ListChirpsByIDs(ctx context.Context, arg ListChirpsByIDsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsByIDs, pq.Array(arg.Ids), arg.ViewerID)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +404,16 @@ func (q *Queries) GetChirps(ctx context.Context) ([]Chirp, error) {
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.InReplyToID,
+			&i.RootID,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.ReactionCounts,
+			&i.RechirpOfID,
+			&i.QuoteOfID,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.ExpiresAt,
 		); err != nil {
 			return nil, err
 		}
@@ -114,3 +427,542 @@ func (q *Queries) GetChirps(ctx context.Context) ([]Chirp, error) {
 	}
 	return items, nil
 }
+
+const listChirpsDesc = `-- name: ListChirpsDesc :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
+FROM chirps
+WHERE deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND ($1::uuid IS NULL OR user_id = $1)
+AND ($1::uuid IS NOT NULL OR visibility <> 'unlisted')
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
+AND NOT chirp_hidden_from(user_id, $2::uuid)
+AND (
+  $3::timestamp IS NULL
+  OR (created_at, id) < ($3, $4::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type ListChirpsDescParams struct {
+	AuthorID        uuid.NullUUID
+	ViewerID        uuid.UUID
+	BeforeCreatedAt sql.NullTime
+	BeforeID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) ListChirpsDesc(ctx context.Context, arg ListChirpsDescParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsDesc,
+		arg.AuthorID,
+		arg.ViewerID,
+		arg.BeforeCreatedAt,
+		arg.BeforeID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyToID,
+			&i.RootID,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.ReactionCounts,
+			&i.RechirpOfID,
+			&i.QuoteOfID,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listChirpsMentioningUser = `-- name: ListChirpsMentioningUser :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
+FROM chirps
+WHERE deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND EXISTS (
+  SELECT 1
+  FROM chirp_entities
+  WHERE chirp_entities.chirp_id = chirps.id
+  AND chirp_entities.mentioned_user_id = $1::uuid
+)
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
+AND NOT chirp_hidden_from(user_id, $2::uuid)
+AND (
+  $3::timestamp IS NULL
+  OR (created_at, id) < ($3, $4::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT $5
+`
+
+type ListChirpsMentioningUserParams struct {
+	UserID          uuid.UUID
+	ViewerID        uuid.UUID
+	BeforeCreatedAt sql.NullTime
+	BeforeID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) ListChirpsMentioningUser(ctx context.Context, arg ListChirpsMentioningUserParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listChirpsMentioningUser,
+		arg.UserID,
+		arg.ViewerID,
+		arg.BeforeCreatedAt,
+		arg.BeforeID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyToID,
+			&i.RootID,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.ReactionCounts,
+			&i.RechirpOfID,
+			&i.QuoteOfID,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExpiredChirpIDs = `-- name: ListExpiredChirpIDs :many
+SELECT id
+FROM chirps
+WHERE expires_at <= NOW()
+ORDER BY expires_at ASC
+LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+func (q *Queries) ListExpiredChirpIDs(ctx context.Context, limit int32) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, listExpiredChirpIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStreamChirpsAfter = `-- name: ListStreamChirpsAfter :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
+FROM chirps
+WHERE (created_at, id) > ($1::timestamp, $2::uuid)
+AND deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND ($3::uuid IS NULL OR user_id = $3)
+AND (
+  $4::text IS NULL
+  OR EXISTS (
+    SELECT 1
+    FROM chirp_entities
+    WHERE chirp_entities.chirp_id = chirps.id
+    AND chirp_entities.kind = 'hashtag'
+    AND lower(chirp_entities.value) = lower($4)
+  )
+)
+AND (($3::uuid IS NOT NULL AND $4::text IS NULL) OR visibility <> 'unlisted')
+AND chirp_visible_to(id, user_id, visibility, $5::uuid)
+AND NOT chirp_hidden_from(user_id, $5::uuid)
+ORDER BY created_at ASC, id ASC
+LIMIT $6
+`
+
+type ListStreamChirpsAfterParams struct {
+	AfterCreatedAt time.Time
+	AfterID        uuid.UUID
+	AuthorID       uuid.NullUUID
+	Tag            sql.NullString
+	ViewerID       uuid.UUID
+	Limit          int32
+}
+
+func (q *Queries) ListStreamChirpsAfter(ctx context.Context, arg ListStreamChirpsAfterParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listStreamChirpsAfter,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.AuthorID,
+		arg.Tag,
+		arg.ViewerID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyToID,
+			&i.RootID,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.ReactionCounts,
+			&i.RechirpOfID,
+			&i.QuoteOfID,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStreamChirpsByIDs = `-- name: ListStreamChirpsByIDs :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
+FROM chirps
+WHERE id = ANY($1::uuid[])
+AND deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND ($2::uuid IS NULL OR user_id = $2)
+AND (
+  $3::text IS NULL
+  OR EXISTS (
+    SELECT 1
+    FROM chirp_entities
+    WHERE chirp_entities.chirp_id = chirps.id
+    AND chirp_entities.kind = 'hashtag'
+    AND lower(chirp_entities.value) = lower($3)
+  )
+)
+AND (($2::uuid IS NOT NULL AND $3::text IS NULL) OR visibility <> 'unlisted')
+AND chirp_visible_to(id, user_id, visibility, $4::uuid)
+AND NOT chirp_hidden_from(user_id, $4::uuid)
+ORDER BY created_at ASC, id ASC
+`
+
+type ListStreamChirpsByIDsParams struct {
+	Ids      []uuid.UUID
+	AuthorID uuid.NullUUID
+	Tag      sql.NullString
+	ViewerID uuid.UUID
+}
+
+func (q *Queries) ListStreamChirpsByIDs(ctx context.Context, arg ListStreamChirpsByIDsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listStreamChirpsByIDs,
+		pq.Array(arg.Ids),
+		arg.AuthorID,
+		arg.Tag,
+		arg.ViewerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyToID,
+			&i.RootID,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.ReactionCounts,
+			&i.RechirpOfID,
+			&i.QuoteOfID,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listThreadChirps = `-- name: ListThreadChirps :many
+SELECT id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
+FROM chirps
+WHERE (id = $1 OR root_id = $1)
+AND (expires_at IS NULL OR expires_at > NOW())
+AND chirp_visible_to(id, user_id, visibility, $2::uuid)
+AND NOT chirp_hidden_from(user_id, $2::uuid)
+ORDER BY created_at ASC, id ASC
+`
+
+type ListThreadChirpsParams struct {
+	RootID   uuid.UUID
+	ViewerID uuid.UUID
+}
+
+func (q *Queries) ListThreadChirps(ctx context.Context, arg ListThreadChirpsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listThreadChirps, arg.RootID, arg.ViewerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyToID,
+			&i.RootID,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.ReactionCounts,
+			&i.RechirpOfID,
+			&i.QuoteOfID,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTimeline = `-- name: ListTimeline :many
+SELECT chirps.id, chirps.created_at, chirps.updated_at, chirps.body, chirps.user_id, chirps.in_reply_to_id, chirps.root_id, chirps.deleted_at, chirps.like_count, chirps.reaction_counts, chirps.rechirp_of_id, chirps.quote_of_id, chirps.visibility, chirps.content_warning, chirps.expires_at
+FROM chirps
+JOIN follows ON follows.followee_id = chirps.user_id
+WHERE follows.follower_id = $1
+AND chirps.deleted_at IS NULL
+AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, $1::uuid)
+AND NOT chirp_hidden_from(chirps.user_id, $1::uuid)
+AND (
+  $2::timestamp IS NULL
+  OR (chirps.created_at, chirps.id) < ($2, $3::uuid)
+)
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT $4
+`
+
+type ListTimelineParams struct {
+	UserID          uuid.UUID
+	BeforeCreatedAt sql.NullTime
+	BeforeID        uuid.NullUUID
+	Limit           int32
+}
+
+func (q *Queries) ListTimeline(ctx context.Context, arg ListTimelineParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, listTimeline,
+		arg.UserID,
+		arg.BeforeCreatedAt,
+		arg.BeforeID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Chirp
+	for rows.Next() {
+		var i Chirp
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Body,
+			&i.UserID,
+			&i.InReplyToID,
+			&i.RootID,
+			&i.DeletedAt,
+			&i.LikeCount,
+			&i.ReactionCounts,
+			&i.RechirpOfID,
+			&i.QuoteOfID,
+			&i.Visibility,
+			&i.ContentWarning,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const purgeChirps = `-- name: PurgeChirps :execrows
+DELETE FROM chirps
+WHERE id = ANY($1::uuid[])
+AND NOT EXISTS (
+  SELECT 1
+  FROM chirps AS replies
+  WHERE replies.in_reply_to_id = chirps.id
+)
+`
+
+func (q *Queries) PurgeChirps(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeChirps, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const tombstoneChirp = `-- name: TombstoneChirp :exec
+UPDATE chirps
+SET body = '', content_warning = NULL, deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2
+`
+
+type TombstoneChirpParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) TombstoneChirp(ctx context.Context, arg TombstoneChirpParams) error {
+	_, err := q.db.ExecContext(ctx, tombstoneChirp, arg.ID, arg.UserID)
+	return err
+}
+
+const tombstoneChirpsWithReplies = `-- name: TombstoneChirpsWithReplies :execrows
+UPDATE chirps
+SET body = '', content_warning = NULL, expires_at = NULL, deleted_at = NOW(), updated_at = NOW()
+WHERE id = ANY($1::uuid[])
+AND EXISTS (
+  SELECT 1
+  FROM chirps AS replies
+  WHERE replies.in_reply_to_id = chirps.id
+)
+`
+
+func (q *Queries) TombstoneChirpsWithReplies(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, tombstoneChirpsWithReplies, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateChirpBody = `-- name: UpdateChirpBody :one
+UPDATE chirps
+SET body = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING id, created_at, updated_at, body, user_id, in_reply_to_id, root_id, deleted_at, like_count, reaction_counts, rechirp_of_id, quote_of_id, visibility, content_warning, expires_at
+`
+
+type UpdateChirpBodyParams struct {
+	ID   uuid.UUID
+	Body string
+}
+
+func (q *Queries) UpdateChirpBody(ctx context.Context, arg UpdateChirpBodyParams) (Chirp, error) {
+	row := q.db.QueryRowContext(ctx, updateChirpBody, arg.ID, arg.Body)
+	var i Chirp
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Body,
+		&i.UserID,
+		&i.InReplyToID,
+		&i.RootID,
+		&i.DeletedAt,
+		&i.LikeCount,
+		&i.ReactionCounts,
+		&i.RechirpOfID,
+		&i.QuoteOfID,
+		&i.Visibility,
+		&i.ContentWarning,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
@@ -6,26 +6,224 @@ package database
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type Block struct {
+	BlockerID uuid.UUID
+	BlockedID uuid.UUID
+	CreatedAt time.Time
+}
+
+type Bookmark struct {
+	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+}
+
 type Chirp struct {
+	ID             uuid.UUID
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	Body           string
+	UserID         uuid.UUID
+	InReplyToID    uuid.NullUUID
+	RootID         uuid.NullUUID
+	DeletedAt      sql.NullTime
+	LikeCount      int32
+	ReactionCounts json.RawMessage
+	RechirpOfID    uuid.NullUUID
+	QuoteOfID      uuid.NullUUID
+	Visibility     string
+	ContentWarning sql.NullString
+	ExpiresAt      sql.NullTime
+}
+
+type ChirpEntity struct {
+	ChirpID         uuid.UUID
+	Kind            string
+	Value           string
+	StartOffset     int32
+	EndOffset       int32
+	MentionedUserID uuid.NullUUID
+}
+
+type ChirpLike struct {
+	ChirpID   uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
+
+type ChirpReaction struct {
+	ChirpID   uuid.UUID
+	UserID    uuid.UUID
+	Emoji     string
+	CreatedAt time.Time
+}
+
+type ChirpRevision struct {
+	ID         uuid.UUID
+	ChirpID    uuid.UUID
+	Body       string
+	CreatedAt  time.Time
+	ReplacedAt time.Time
+}
+
+type Conversation struct {
+	ID              uuid.UUID
+	IsGroup         bool
+	Title           sql.NullString
+	FilterProfanity bool
+	CreatedBy       uuid.NullUUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type ConversationMember struct {
+	ConversationID uuid.UUID
+	UserID         uuid.UUID
+	JoinedAt       time.Time
+	LastReadAt     time.Time
+}
+
+type Draft struct {
+	ID               uuid.UUID
+	UserID           uuid.UUID
+	Body             string
+	InReplyToID      uuid.NullUUID
+	QuoteOfID        uuid.NullUUID
+	MediaIds         []uuid.UUID
+	PublishAt        sql.NullTime
+	Status           string
+	Error            sql.NullString
+	ChirpID          uuid.NullUUID
+	PublishedAt      sql.NullTime
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	Visibility       string
+	ContentWarning   sql.NullString
+	ExpiresInSeconds sql.NullInt32
+}
+
+type Follow struct {
+	FollowerID uuid.UUID
+	FolloweeID uuid.UUID
+	CreatedAt  time.Time
+}
+
+type JwtKey struct {
+	ID          string
+	Algorithm   string
+	PrivateKey  string
+	CreatedAt   time.Time
+	ActivatesAt time.Time
+	RetiresAt   sql.NullTime
+}
+
+type MediaAttachment struct {
+	ID           uuid.UUID
+	UserID       uuid.UUID
+	ChirpID      uuid.NullUUID
+	Position     int32
+	ContentType  string
+	Width        int32
+	Height       int32
+	SizeBytes    int64
+	StorageKey   string
+	ThumbnailKey string
+	CreatedAt    time.Time
+}
+
+type Message struct {
+	ID             uuid.UUID
+	ConversationID uuid.UUID
+	SenderID       uuid.NullUUID
+	Body           string
+	CreatedAt      time.Time
+}
+
+type Mute struct {
+	MuterID   uuid.UUID
+	MutedID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type MutedKeyword struct {
 	ID        uuid.UUID
+	UserID    uuid.UUID
+	Phrase    string
+	WholeWord bool
+	ExpiresAt sql.NullTime
+	CreatedAt time.Time
+}
+
+type Notification struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Kind      string
+	ChirpID   uuid.NullUUID
+	GroupKey  string
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	Body      string
+	ReadAt    sql.NullTime
+}
+
+type NotificationActor struct {
+	NotificationID uuid.UUID
+	ActorID        uuid.UUID
+	CreatedAt      time.Time
+}
+
+type PinnedChirp struct {
 	UserID    uuid.UUID
+	ChirpID   uuid.UUID
+	CreatedAt time.Time
+}
+
+type Poll struct {
+	ChirpID     uuid.UUID
+	ClosesAt    time.Time
+	FinalizedAt sql.NullTime
+	CreatedAt   time.Time
+}
+
+type PollOption struct {
+	ChirpID   uuid.UUID
+	Position  int32
+	Label     string
+	VoteCount int32
+}
+
+type PollVote struct {
+	ChirpID   uuid.UUID
+	UserID    uuid.UUID
+	Position  int32
+	CreatedAt time.Time
 }
 
 type RefreshToken struct {
-	Token     string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	UserID           uuid.UUID
+	ExpiresAt        time.Time
+	RevokedAt        sql.NullTime
+	FamilyID         uuid.UUID
+	RotatedAt        sql.NullTime
+	LookupID         string
+	TokenHash        string
+	SessionStartedAt time.Time
+	LastUsedAt       time.Time
+	UserAgent        string
+	Ip               string
+}
+
+type RestrictedWord struct {
+	ID        uuid.UUID
+	Pattern   string
 	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.UUID
-	ExpiresAt time.Time
-	RevokedAt sql.NullTime
 }
 
 type User struct {
@@ -35,4 +233,5 @@ type User struct {
 	Email          string
 	HashedPassword string
 	IsChirpyRed    bool
+	Handle         sql.NullString
 }
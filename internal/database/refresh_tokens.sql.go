@@ -14,71 +14,226 @@ import (
 
 const createRefreshToken = `-- name: CreateRefreshToken :one
 INSERT INTO refresh_tokens (
-  token,
+  lookup_id,
+  token_hash,
   created_at,
   updated_at,
   user_id,
-  expires_at
+  expires_at,
+  family_id,
+  session_started_at,
+  last_used_at,
+  user_agent,
+  ip
 ) VALUES (
   $1,
+  $2,
   NOW(),
   NOW(),
-  $2,
-  $3
+  $3,
+  $4,
+  $5,
+  $6,
+  NOW(),
+  $7,
+  $8
 )
-RETURNING token, created_at, updated_at, user_id, expires_at, revoked_at
+RETURNING created_at, updated_at, user_id, expires_at, revoked_at, family_id, rotated_at, lookup_id, token_hash, session_started_at, last_used_at, user_agent, ip
 `
 
 type CreateRefreshTokenParams struct {
-	Token     string
-	UserID    uuid.UUID
-	ExpiresAt time.Time
+	LookupID         string
+	TokenHash        string
+	UserID           uuid.UUID
+	ExpiresAt        time.Time
+	FamilyID         uuid.UUID
+	SessionStartedAt time.Time
+	UserAgent        string
+	Ip               string
 }
 
 func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) (RefreshToken, error) {
-	row := q.db.QueryRowContext(ctx, createRefreshToken, arg.Token, arg.UserID, arg.ExpiresAt)
+	row := q.db.QueryRowContext(ctx, createRefreshToken,
+		arg.LookupID,
+		arg.TokenHash,
+		arg.UserID,
+		arg.ExpiresAt,
+		arg.FamilyID,
+		arg.SessionStartedAt,
+		arg.UserAgent,
+		arg.Ip,
+	)
 	var i RefreshToken
 	err := row.Scan(
-		&i.Token,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.ExpiresAt,
 		&i.RevokedAt,
+		&i.FamilyID,
+		&i.RotatedAt,
+		&i.LookupID,
+		&i.TokenHash,
+		&i.SessionStartedAt,
+		&i.LastUsedAt,
+		&i.UserAgent,
+		&i.Ip,
 	)
 	return i, err
 }
 
-const getUserFromRefreshToken = `-- name: GetUserFromRefreshToken :one
-SELECT users.id, users.created_at, users.updated_at, users.email, users.hashed_password, users.is_chirpy_red
-FROM users
-JOIN refresh_tokens ON users.ID = refresh_tokens.user_id
-WHERE refresh_tokens.token = $1 
-AND refresh_tokens.revoked_at IS NULL 
-AND refresh_tokens.expires_at > NOW()
+const getRefreshTokenForUpdate = `-- name: GetRefreshTokenForUpdate :one
+SELECT created_at, updated_at, user_id, expires_at, revoked_at, family_id, rotated_at, lookup_id, token_hash, session_started_at, last_used_at, user_agent, ip
+FROM refresh_tokens
+WHERE lookup_id = $1 AND token_hash = $2
+FOR UPDATE
 `
 
-func (q *Queries) GetUserFromRefreshToken(ctx context.Context, token string) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserFromRefreshToken, token)
-	var i User
+type GetRefreshTokenForUpdateParams struct {
+	LookupID  string
+	TokenHash string
+}
+
+func (q *Queries) GetRefreshTokenForUpdate(ctx context.Context, arg GetRefreshTokenForUpdateParams) (RefreshToken, error) {
+	row := q.db.QueryRowContext(ctx, getRefreshTokenForUpdate, arg.LookupID, arg.TokenHash)
+	var i RefreshToken
 	err := row.Scan(
-		&i.ID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
-		&i.Email,
-		&i.HashedPassword,
-		&i.IsChirpyRed,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.FamilyID,
+		&i.RotatedAt,
+		&i.LookupID,
+		&i.TokenHash,
+		&i.SessionStartedAt,
+		&i.LastUsedAt,
+		&i.UserAgent,
+		&i.Ip,
 	)
 	return i, err
 }
 
+const listSessions = `-- name: ListSessions :many
+SELECT created_at, updated_at, user_id, expires_at, revoked_at, family_id, rotated_at, lookup_id, token_hash, session_started_at, last_used_at, user_agent, ip
+FROM refresh_tokens
+WHERE user_id = $1
+AND revoked_at IS NULL
+AND rotated_at IS NULL
+AND expires_at > NOW()
+ORDER BY last_used_at DESC, family_id
+`
+
+func (q *Queries) ListSessions(ctx context.Context, userID uuid.UUID) ([]RefreshToken, error) {
+	rows, err := q.db.QueryContext(ctx, listSessions, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []RefreshToken
+	for rows.Next() {
+		var i RefreshToken
+		if err := rows.Scan(
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.ExpiresAt,
+			&i.RevokedAt,
+			&i.FamilyID,
+			&i.RotatedAt,
+			&i.LookupID,
+			&i.TokenHash,
+			&i.SessionStartedAt,
+			&i.LastUsedAt,
+			&i.UserAgent,
+			&i.Ip,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeAllSessions = `-- name: RevokeAllSessions :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeAllSessions(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, revokeAllSessions, userID)
+	return err
+}
+
 const revokeRefreshToken = `-- name: RevokeRefreshToken :exec
 UPDATE refresh_tokens
 SET revoked_at = NOW(), updated_at = NOW()
-WHERE token = $1
+WHERE lookup_id = $1 AND token_hash = $2
 `
 
-func (q *Queries) RevokeRefreshToken(ctx context.Context, token string) error {
-	_, err := q.db.ExecContext(ctx, revokeRefreshToken, token)
+type RevokeRefreshTokenParams struct {
+	LookupID  string
+	TokenHash string
+}
+
+func (q *Queries) RevokeRefreshToken(ctx context.Context, arg RevokeRefreshTokenParams) error {
+	_, err := q.db.ExecContext(ctx, revokeRefreshToken, arg.LookupID, arg.TokenHash)
+	return err
+}
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeRefreshTokenFamily, familyID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeSession = `-- name: RevokeSession :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE family_id = $1 AND user_id = $2 AND revoked_at IS NULL
+`
+
+type RevokeSessionParams struct {
+	FamilyID uuid.UUID
+	UserID   uuid.UUID
+}
+
+func (q *Queries) RevokeSession(ctx context.Context, arg RevokeSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeSession, arg.FamilyID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const rotateRefreshToken = `-- name: RotateRefreshToken :exec
+UPDATE refresh_tokens
+SET rotated_at = $1::timestamp, updated_at = NOW()
+WHERE lookup_id = $2
+`
+
+type RotateRefreshTokenParams struct {
+	RotatedAt time.Time
+	LookupID  string
+}
+
+func (q *Queries) RotateRefreshToken(ctx context.Context, arg RotateRefreshTokenParams) error {
+	_, err := q.db.ExecContext(ctx, rotateRefreshToken, arg.RotatedAt, arg.LookupID)
 	return err
 }
@@ -0,0 +1,239 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// MaxPixels bounds the decoded size of an upload, so a small file that
+	// claims huge dimensions can't exhaust memory.
+	MaxPixels = 40_000_000
+	// MaxFrames bounds the number of frames in an animated GIF. Together the
+	// frames also have to fit within MaxPixels.
+	MaxFrames = 500
+	// ThumbnailSize is the longest edge of a generated thumbnail.
+	ThumbnailSize = 400
+
+	jpegQuality = 85
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrTooManyPixels   = errors.New("image dimensions are too large")
+	ErrTooManyFrames   = errors.New("animation has too many frames")
+	errMalformedGIF    = errors.New("malformed gif")
+)
+
+// Image is an upload that has been decoded and re-encoded. Re-encoding drops
+// EXIF and any other metadata the original carried.
+type Image struct {
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+	Data        []byte
+
+	ThumbnailContentType string
+	ThumbnailExtension   string
+	Thumbnail            []byte
+}
+
+// Process validates an uploaded image by sniffing its content, re-encodes it
+// without metadata and renders a thumbnail. JPEG orientation is applied to
+// the pixels before the EXIF block that carried it is dropped.
+func Process(data []byte) (*Image, error) {
+	contentType := http.DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, ErrUnsupportedType
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	if contentType == "image/gif" {
+		return processGIF(data)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Image{ContentType: contentType}
+	buf := bytes.Buffer{}
+	thumb := bytes.Buffer{}
+	if contentType == "image/jpeg" {
+		img = applyOrientation(img, jpegOrientation(data))
+		out.Extension = ".jpg"
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		if err := jpeg.Encode(&thumb, thumbnail(img), &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		out.ThumbnailContentType, out.ThumbnailExtension = "image/jpeg", ".jpg"
+	} else {
+		out.Extension = ".png"
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		if err := png.Encode(&thumb, thumbnail(img)); err != nil {
+			return nil, err
+		}
+		out.ThumbnailContentType, out.ThumbnailExtension = "image/png", ".png"
+	}
+
+	out.Width, out.Height = img.Bounds().Dx(), img.Bounds().Dy()
+	out.Data = buf.Bytes()
+	out.Thumbnail = thumb.Bytes()
+	return out, nil
+}
+
+// processGIF keeps every frame of an animation; the thumbnail is a still of
+// the first one. The frames are counted and measured before any of them is
+// decoded, since each one is held in memory at full size.
+func processGIF(data []byte) (*Image, error) {
+	frames, pixels, err := gifFrames(data)
+	if err != nil {
+		return nil, err
+	}
+	if frames > MaxFrames {
+		return nil, ErrTooManyFrames
+	}
+	if pixels > MaxPixels {
+		return nil, ErrTooManyPixels
+	}
+
+	anim, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(anim.Image) == 0 {
+		return nil, errors.New("gif has no frames")
+	}
+
+	buf := bytes.Buffer{}
+	if err := gif.EncodeAll(&buf, &gif.GIF{
+		Image:     anim.Image,
+		Delay:     anim.Delay,
+		LoopCount: anim.LoopCount,
+		Disposal:  anim.Disposal,
+		Config:    anim.Config,
+	}); err != nil {
+		return nil, err
+	}
+
+	thumb := bytes.Buffer{}
+	if err := png.Encode(&thumb, thumbnail(anim.Image[0])); err != nil {
+		return nil, err
+	}
+
+	return &Image{
+		ContentType:          "image/gif",
+		Extension:            ".gif",
+		Width:                anim.Config.Width,
+		Height:               anim.Config.Height,
+		Data:                 buf.Bytes(),
+		ThumbnailContentType: "image/png",
+		ThumbnailExtension:   ".png",
+		Thumbnail:            thumb.Bytes(),
+	}, nil
+}
+
+// gifFrames walks the blocks of a GIF without decoding any image data, and
+// returns how many frames it has and their combined area. It stops early
+// once either passes its limit. A truncated file is left to the decoder to
+// reject.
+func gifFrames(data []byte) (frames, pixels int, err error) {
+	const (
+		extensionIntroducer = 0x21
+		imageSeparator      = 0x2C
+		trailer             = 0x3B
+		colorTableFlag      = 0x80
+	)
+
+	if len(data) < 13 {
+		return 0, 0, errMalformedGIF
+	}
+	pos := 13
+	if flags := data[10]; flags&colorTableFlag != 0 {
+		pos += 3 << (flags&0x07 + 1)
+	}
+
+	for pos < len(data) && frames <= MaxFrames && pixels <= MaxPixels {
+		switch data[pos] {
+		case extensionIntroducer:
+			pos = skipGIFSubBlocks(data, pos+2)
+		case imageSeparator:
+			if pos+10 > len(data) {
+				return frames, pixels, nil
+			}
+			width := int(binary.LittleEndian.Uint16(data[pos+5:]))
+			height := int(binary.LittleEndian.Uint16(data[pos+7:]))
+			flags := data[pos+9]
+			pos += 10
+			if flags&colorTableFlag != 0 {
+				pos += 3 << (flags&0x07 + 1)
+			}
+			// Skip the LZW minimum code size, then the image data.
+			pos = skipGIFSubBlocks(data, pos+1)
+			frames++
+			pixels += width * height
+		case trailer:
+			return frames, pixels, nil
+		default:
+			return 0, 0, errMalformedGIF
+		}
+	}
+	return frames, pixels, nil
+}
+
+// skipGIFSubBlocks returns the position just past the chain of data
+// sub-blocks starting at pos.
+func skipGIFSubBlocks(data []byte, pos int) int {
+	for pos < len(data) {
+		size := int(data[pos])
+		pos++
+		if size == 0 {
+			return pos
+		}
+		pos += size
+	}
+	return len(data)
+}
+
+// thumbnail scales img to fit within ThumbnailSize, keeping its aspect ratio.
+// Images that already fit are copied unscaled.
+func thumbnail(img image.Image) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > ThumbnailSize || height > ThumbnailSize {
+		if width >= height {
+			height = max(1, height*ThumbnailSize/width)
+			width = ThumbnailSize
+		} else {
+			width = max(1, width*ThumbnailSize/height)
+			height = ThumbnailSize
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
@@ -0,0 +1,153 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 0, 255})
+		}
+	}
+	return img
+}
+
+// withOrientation splices a minimal EXIF block carrying an orientation tag
+// in after the JPEG start-of-image marker.
+func withOrientation(jpg []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	app1 := []byte{0xFF, 0xE1}
+	app1 = binary.BigEndian.AppendUint16(app1, uint16(len(segment)+2))
+	app1 = append(app1, segment...)
+
+	out := append([]byte{}, jpg[:2]...)
+	out = append(out, app1...)
+	return append(out, jpg[2:]...)
+}
+
+func TestProcessJPEGStripsEXIFAndRotates(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := jpeg.Encode(&buf, testImage(20, 10), nil); err != nil {
+		t.Fatal(err)
+	}
+	data := withOrientation(buf.Bytes(), 6)
+	if jpegOrientation(data) != 6 {
+		t.Fatalf("test fixture has orientation %d, want 6", jpegOrientation(data))
+	}
+
+	img, err := Process(data)
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if img.ContentType != "image/jpeg" || img.Extension != ".jpg" {
+		t.Errorf("got %s %s, want image/jpeg .jpg", img.ContentType, img.Extension)
+	}
+	if img.Width != 10 || img.Height != 20 {
+		t.Errorf("got %dx%d, want 10x20", img.Width, img.Height)
+	}
+	if bytes.Contains(img.Data, []byte("Exif")) {
+		t.Error("re-encoded image still contains EXIF")
+	}
+}
+
+func TestProcessPNGThumbnail(t *testing.T) {
+	buf := bytes.Buffer{}
+	if err := png.Encode(&buf, testImage(800, 200)); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := Process(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+
+	thumb, err := png.Decode(bytes.NewReader(img.Thumbnail))
+	if err != nil {
+		t.Fatalf("decoding thumbnail: %v", err)
+	}
+	if got := thumb.Bounds().Size(); got != image.Pt(ThumbnailSize, 100) {
+		t.Errorf("thumbnail is %v, want %v", got, image.Pt(ThumbnailSize, 100))
+	}
+}
+
+func TestProcessRejectsNonImages(t *testing.T) {
+	if _, err := Process([]byte("<html><body>hi</body></html>")); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("got %v, want ErrUnsupportedType", err)
+	}
+}
+
+func TestProcessGIFRejectsTooManyFrames(t *testing.T) {
+	anim := &gif.GIF{}
+	for range MaxFrames + 1 {
+		anim.Image = append(anim.Image, image.NewPaletted(image.Rect(0, 0, 1, 1), color.Palette{color.Black, color.White}))
+		anim.Delay = append(anim.Delay, 0)
+	}
+	buf := bytes.Buffer{}
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Process(buf.Bytes()); !errors.Is(err, ErrTooManyFrames) {
+		t.Errorf("got %v, want ErrTooManyFrames", err)
+	}
+}
+
+// TestProcessGIFRejectsLargeAnimations uses frames that each fit within
+// MaxPixels but don't together. Their image data is left empty, since the
+// limit has to hold before any of it is decoded.
+func TestProcessGIFRejectsLargeAnimations(t *testing.T) {
+	const size = 4000
+	data := []byte("GIF89a")
+	data = binary.LittleEndian.AppendUint16(data, size)
+	data = binary.LittleEndian.AppendUint16(data, size)
+	data = append(data, 0, 0, 0)
+	for range 3 {
+		data = append(data, 0x2C, 0, 0, 0, 0)
+		data = binary.LittleEndian.AppendUint16(data, size)
+		data = binary.LittleEndian.AppendUint16(data, size)
+		data = append(data, 0, 2, 0)
+	}
+	data = append(data, 0x3B)
+
+	if _, err := Process(data); !errors.Is(err, ErrTooManyPixels) {
+		t.Errorf("got %v, want ErrTooManyPixels", err)
+	}
+}
+
+func TestProcessGIFKeepsFrames(t *testing.T) {
+	anim := &gif.GIF{}
+	for range 3 {
+		anim.Image = append(anim.Image, image.NewPaletted(image.Rect(0, 0, 10, 10), color.Palette{color.Black, color.White}))
+		anim.Delay = append(anim.Delay, 10)
+	}
+	buf := bytes.Buffer{}
+	if err := gif.EncodeAll(&buf, anim); err != nil {
+		t.Fatal(err)
+	}
+
+	frames, pixels, err := gifFrames(buf.Bytes())
+	if err != nil || frames != 3 || pixels != 300 {
+		t.Errorf("gifFrames = %d, %d, %v, want 3, 300, nil", frames, pixels, err)
+	}
+	if _, err := Process(buf.Bytes()); err != nil {
+		t.Errorf("Process: %v", err)
+	}
+}
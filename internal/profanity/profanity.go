@@ -0,0 +1,325 @@
+package profanity
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+const (
+	ReplacementWord = "****"
+
+	// leetSymbols are the symbols Tokenize keeps inside a word.
+	leetSymbols = "@$!"
+)
+
+// leetReplacer undoes the common character swaps used to dodge filters.
+var leetReplacer = strings.NewReplacer(
+	"0", "o",
+	"1", "i",
+	"3", "e",
+	"4", "a",
+	"5", "s",
+	"7", "t",
+	"@", "a",
+	"$", "s",
+	"!", "i",
+)
+
+// Filter censors restricted words in text. A rule is either a plain word, a
+// wildcard pattern where * matches any run of characters, or a regular
+// expression wrapped in slashes such as /^fornax(es)?$/. Plain and wildcard
+// rules also catch leetspeak and stretched letters ("f0rnaaax"), but never
+// fewer letters than the rule has, so "butt" doesn't catch "but".
+type Filter struct {
+	// words maps a plain rule with its repeats collapsed to the minimum
+	// length of each run of letters, for every rule that collapses to it.
+	words     map[string][][]int
+	wildcards []*regexp.Regexp
+	patterns  []*regexp.Regexp
+}
+
+func newFilter() *Filter {
+	return &Filter{words: map[string][][]int{}}
+}
+
+func New(rules []string) (*Filter, error) {
+	filter := newFilter()
+	for _, rule := range rules {
+		if err := filter.add(rule); err != nil {
+			return nil, err
+		}
+	}
+	return filter, nil
+}
+
+// ValidateRule reports whether a rule could be loaded into a Filter.
+func ValidateRule(rule string) error {
+	return newFilter().add(rule)
+}
+
+func (f *Filter) add(rule string) error {
+	rule = strings.TrimSpace(rule)
+	if rule == "" {
+		return errors.New("empty rule")
+	}
+
+	if len(rule) > 2 && strings.HasPrefix(rule, "/") && strings.HasSuffix(rule, "/") {
+		re, err := regexp.Compile("(?i)" + rule[1:len(rule)-1])
+		if err != nil {
+			return err
+		}
+		f.patterns = append(f.patterns, re)
+		return nil
+	}
+
+	if strings.Contains(rule, "*") {
+		parts := strings.Split(Normalize(rule), "*")
+		for i, part := range parts {
+			parts[i] = runPattern(part)
+		}
+		f.wildcards = append(f.wildcards, regexp.MustCompile("^"+strings.Join(parts, ".*")+"$"))
+		return nil
+	}
+
+	collapsed, counts := letterRuns(Normalize(rule))
+	f.words[collapsed] = append(f.words[collapsed], counts)
+	return nil
+}
+
+// runPattern matches s with each run of a letter stretched to at least its
+// length, so "ss" becomes s{2,}.
+func runPattern(s string) string {
+	collapsed, counts := letterRuns(s)
+	pattern := strings.Builder{}
+	for i, r := range []rune(collapsed) {
+		pattern.WriteString(regexp.QuoteMeta(string(r)))
+		if counts[i] == 1 {
+			pattern.WriteString("+")
+		} else {
+			pattern.WriteString(fmt.Sprintf("{%d,}", counts[i]))
+		}
+	}
+	return pattern.String()
+}
+
+// Clean replaces every restricted word in message with ReplacementWord and
+// leaves everything between words untouched.
+func (f *Filter) Clean(message string) string {
+	cleaned := strings.Builder{}
+	for _, token := range Tokenize(message) {
+		if token.IsWord && f.matches(token.Text) {
+			cleaned.WriteString(ReplacementWord)
+			continue
+		}
+		cleaned.WriteString(token.Text)
+	}
+	return cleaned.String()
+}
+
+// matches reports whether word, or any piece of it between leetspeak
+// symbols, breaks a rule. Checking the pieces catches words glued together
+// or prefixed with a symbol ("hello!kerfuffle", "@kerfuffle") without losing
+// leetspeak inside a word ("sh@rbert").
+func (f *Filter) matches(word string) bool {
+	if f.matchesWord(word) {
+		return true
+	}
+
+	pieces := strings.FieldsFunc(word, func(r rune) bool {
+		return strings.ContainsRune(leetSymbols, r)
+	})
+	if len(pieces) == 1 && pieces[0] == word {
+		return false
+	}
+	for _, piece := range pieces {
+		if f.matchesWord(piece) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Filter) matchesWord(word string) bool {
+	lowered := trimPossessive(strings.ToLower(word))
+	normalized := Normalize(lowered)
+
+	collapsed, counts := letterRuns(normalized)
+	for _, want := range f.words[collapsed] {
+		if atLeast(counts, want) {
+			return true
+		}
+	}
+
+	for _, re := range f.wildcards {
+		if re.MatchString(lowered) || re.MatchString(normalized) {
+			return true
+		}
+	}
+
+	// Regular expressions can't be stretched like the other rules, so the
+	// input is also tried with its repeated letters squeezed.
+	squeezed := collapseRepeats(normalized)
+	for _, re := range f.patterns {
+		if re.MatchString(lowered) || re.MatchString(normalized) || re.MatchString(squeezed) {
+			return true
+		}
+	}
+	return false
+}
+
+// trimPossessive drops a trailing "'s" or "'", so "kerfuffle's" is checked
+// as "kerfuffle".
+func trimPossessive(word string) string {
+	for _, suffix := range []string{"'s", "\u2019s", "'", "\u2019"} {
+		if trimmed, ok := strings.CutSuffix(word, suffix); ok && trimmed != "" {
+			return trimmed
+		}
+	}
+	return word
+}
+
+func atLeast(counts, want []int) bool {
+	for i := range want {
+		if counts[i] < want[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Token is a run of text between Unicode word boundaries. Start and End are
+// byte offsets into the tokenized string.
+type Token struct {
+	Text   string
+	Start  int
+	End    int
+	IsWord bool
+}
+
+// Tokenize splits text on Unicode (UAX #29) word boundaries and on
+// underscores, which UAX #29 counts as part of a word. Leetspeak symbols
+// glued to a word, as in "sh@rbert", are kept as part of it.
+func Tokenize(text string) []Token {
+	segments := []Token{}
+	offset := 0
+	state := -1
+	rest := text
+	for len(rest) > 0 {
+		var segment string
+		segment, rest, state = uniseg.FirstWordInString(rest, state)
+		for _, part := range splitUnderscores(segment) {
+			segments = append(segments, Token{
+				Text:   part,
+				Start:  offset,
+				End:    offset + len(part),
+				IsWord: isWordSegment(part),
+			})
+			offset += len(part)
+		}
+	}
+
+	tokens := []Token{}
+	for i := 0; i < len(segments); i++ {
+		token := segments[i]
+		for i+1 < len(segments) {
+			next := segments[i+1]
+			switch {
+			case token.IsWord && isLeetSegment(next.Text) && i+2 < len(segments) && segments[i+2].IsWord:
+				token = joinTokens(token, next, segments[i+2])
+				i += 2
+				continue
+			case !token.IsWord && isLeetSegment(token.Text) && next.IsWord && (len(tokens) == 0 || !tokens[len(tokens)-1].IsWord):
+				token = joinTokens(token, next)
+				i++
+				continue
+			}
+			break
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
+
+// Normalize lower-cases a word and undoes leetspeak substitutions.
+func Normalize(word string) string {
+	return leetReplacer.Replace(strings.ToLower(word))
+}
+
+// splitUnderscores splits segment into the runs of underscores and the text
+// between them.
+func splitUnderscores(segment string) []string {
+	if !strings.Contains(segment, "_") || segment == strings.Repeat("_", len(segment)) {
+		return []string{segment}
+	}
+
+	parts := []string{}
+	start := 0
+	for i := 1; i <= len(segment); i++ {
+		if i == len(segment) || (segment[i] == '_') != (segment[start] == '_') {
+			parts = append(parts, segment[start:i])
+			start = i
+		}
+	}
+	return parts
+}
+
+// letterRuns splits word into its letters with repeats collapsed and the
+// length of each run, so "kerfuffle" gives "kerfufle" and 1,1,1,1,1,2,1,1.
+func letterRuns(word string) (string, []int) {
+	collapsed := strings.Builder{}
+	counts := []int{}
+	var last rune
+	for i, r := range word {
+		if i > 0 && r == last {
+			counts[len(counts)-1]++
+			continue
+		}
+		collapsed.WriteRune(r)
+		counts = append(counts, 1)
+		last = r
+	}
+	return collapsed.String(), counts
+}
+
+// collapseRepeats squeezes runs of the same letter, so "kerfuuuffle" and
+// "kerfuffle" both become "kerfufle".
+func collapseRepeats(word string) string {
+	collapsed := strings.Builder{}
+	var last rune
+	for i, r := range word {
+		if i > 0 && r == last {
+			continue
+		}
+		collapsed.WriteRune(r)
+		last = r
+	}
+	return collapsed.String()
+}
+
+func isWordSegment(segment string) bool {
+	for _, r := range segment {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func isLeetSegment(segment string) bool {
+	r, size := utf8.DecodeRuneInString(segment)
+	return size == len(segment) && strings.ContainsRune(leetSymbols, r)
+}
+
+func joinTokens(tokens ...Token) Token {
+	joined := Token{Start: tokens[0].Start, End: tokens[len(tokens)-1].End, IsWord: true}
+	for _, token := range tokens {
+		joined.Text += token.Text
+	}
+	return joined
+}
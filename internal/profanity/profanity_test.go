@@ -0,0 +1,159 @@
+package profanity
+
+import (
+	"fmt"
+	"testing"
+)
+
+var defaultRules = []string{"kerfuffle", "sharbert", "fornax"}
+
+func TestFilterClean(t *testing.T) {
+	cases := []struct {
+		rules []string
+		input string
+		want  string
+	}{
+		{
+			rules: defaultRules,
+			input: "This is a kerfuffle opinion I need to share with the world",
+			want:  "This is a **** opinion I need to share with the world",
+		},
+		{
+			rules: defaultRules,
+			input: "I had something interesting for breakfast",
+			want:  "I had something interesting for breakfast",
+		},
+		{
+			rules: defaultRules,
+			input: "What a Kerfuffle!",
+			want:  "What a ****!",
+		},
+		{
+			rules: defaultRules,
+			input: "kerfuffle, sharbert and\tfornax.",
+			want:  "****, **** and\t****.",
+		},
+		{
+			rules: defaultRules,
+			input: "k3rfuffl3 and sh@rbert and f0rn4x",
+			want:  "**** and **** and ****",
+		},
+		{
+			rules: defaultRules,
+			input: "what a kerfuuuufffle",
+			want:  "what a ****",
+		},
+		{
+			rules: defaultRules,
+			input: "fornaxes are fine",
+			want:  "fornaxes are fine",
+		},
+		{
+			rules: []string{"shar*"},
+			input: "sharberts and sharks, not shards of glass",
+			want:  "**** and ****, not **** of glass",
+		},
+		{
+			rules: []string{"/^fornax(es)?$/"},
+			input: "Fornaxes everywhere, fornax",
+			want:  "**** everywhere, ****",
+		},
+		{
+			rules: []string{"ass", "butt", "hell", "book"},
+			input: "as but hel bok",
+			want:  "as but hel bok",
+		},
+		{
+			rules: []string{"ass", "butt", "hell", "book"},
+			input: "asss buttt heeell boook",
+			want:  "**** **** **** ****",
+		},
+		{
+			rules: []string{"kerfuffle"},
+			input: "kerfufle",
+			want:  "kerfufle",
+		},
+		{
+			rules: []string{"boo*"},
+			input: "bother boots",
+			want:  "bother ****",
+		},
+		{
+			rules: defaultRules,
+			input: "the kerfuffle's fault",
+			want:  "the **** fault",
+		},
+		{
+			rules: defaultRules,
+			input: "!kerfuffle @kerfuffle hello!kerfuffle",
+			want:  "**** **** ****",
+		},
+		{
+			rules: defaultRules,
+			input: "$harbert",
+			want:  "****",
+		},
+		{
+			rules: defaultRules,
+			input: "fornax_fornax and kerfuffle__x",
+			want:  "****_**** and ****__x",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%q", c.input), func(t *testing.T) {
+			filter, err := New(c.rules)
+			if err != nil {
+				t.Fatalf("New(%q): %v", c.rules, err)
+			}
+			if got := filter.Clean(c.input); got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestValidateRule(t *testing.T) {
+	cases := []struct {
+		rule    string
+		wantErr bool
+	}{
+		{rule: "kerfuffle"},
+		{rule: "ker*"},
+		{rule: "/^ker.*$/"},
+		{rule: "  ", wantErr: true},
+		{rule: "/([a-z/", wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.rule, func(t *testing.T) {
+			err := ValidateRule(c.rule)
+			if (err != nil) != c.wantErr {
+				t.Errorf("ValidateRule(%q) error = %v, wantErr %v", c.rule, err, c.wantErr)
+			}
+		})
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	tokens := Tokenize("Hi, sh@rbert! a_b")
+	want := []Token{
+		{Text: "Hi", Start: 0, End: 2, IsWord: true},
+		{Text: ",", Start: 2, End: 3},
+		{Text: " ", Start: 3, End: 4},
+		{Text: "sh@rbert", Start: 4, End: 12, IsWord: true},
+		{Text: "!", Start: 12, End: 13},
+		{Text: " ", Start: 13, End: 14},
+		{Text: "a", Start: 14, End: 15, IsWord: true},
+		{Text: "_", Start: 15, End: 16},
+		{Text: "b", Start: 16, End: 17, IsWord: true},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("got %d tokens %+v, want %d", len(tokens), tokens, len(want))
+	}
+	for i := range want {
+		if tokens[i] != want[i] {
+			t.Errorf("token %d: got %+v, want %+v", i, tokens[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	jwtKeyRefreshInterval = time.Minute
+	// jwtKeyRotationPeriod is how long a key signs before it's replaced.
+	jwtKeyRotationPeriod = 30 * 24 * time.Hour
+	// jwtKeyPrepublish is how long a new key sits in the JWKS before it
+	// signs anything, so services that cache the JWKS see it in time.
+	jwtKeyPrepublish = 24 * time.Hour
+	// jwtKeyRetireAfter keeps a replaced key verifying until every token it
+	// signed has expired.
+	jwtKeyRetireAfter = expiresIn + 5*time.Minute
+)
+
+// jwtKeys returns the keyring currently in use. Until one has loaded it's
+// empty, so every token is rejected and none can be issued.
+func (cfg *apiConfig) jwtKeys() *auth.Keyring {
+	if keyring := cfg.signingKeys.Load(); keyring != nil {
+		return keyring
+	}
+	return auth.NewKeyring()
+}
+
+// reloadJWTKeys rebuilds the keyring from the jwt_keys table. Keys that
+// can't be decrypted are skipped rather than taking the others down, but a
+// keyring that can't sign anything is an error and isn't swapped in.
+func (cfg *apiConfig) reloadJWTKeys(ctx context.Context) error {
+	rows, err := cfg.db.ListJWTKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*auth.SigningKey, 0, len(rows))
+	for _, row := range rows {
+		private, err := auth.DecryptPrivateKey(row.PrivateKey, cfg.jwtSecret)
+		if err != nil {
+			log.Printf("Skipping JWT key %s: %v", row.ID, err)
+			continue
+		}
+		key, err := auth.NewSigningKey(row.ID, row.Algorithm, private, row.ActivatesAt, row.RetiresAt.Time)
+		if err != nil {
+			log.Printf("Skipping JWT key %s: %v", row.ID, err)
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	keyring := auth.NewKeyring(keys...)
+	if !keyring.CanSign() {
+		return auth.ErrNoSigningKey
+	}
+	cfg.signingKeys.Store(keyring)
+	return nil
+}
+
+// rotateJWTKeys makes sure there's a key to sign with and schedules the next
+// one when the current key is due for replacement or uses a different
+// algorithm than configured. Keys this instance can't decrypt, say after
+// JWT_SECRET changed, are ignored, so a readable key replaces them at once.
+// Instances take turns through an advisory lock, so only one of them adds
+// each key.
+func (cfg *apiConfig) rotateJWTKeys(ctx context.Context) error {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	if err := qtx.LockJWTKeys(ctx); err != nil {
+		return err
+	}
+
+	rows, err := qtx.ListJWTKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	latest, ok := cfg.latestReadableJWTKey(rows)
+	switch {
+	case !ok:
+		// Nothing can be signed without a key, so the first one is used
+		// straight away.
+		if err := cfg.createJWTKey(ctx, qtx, now); err != nil {
+			return err
+		}
+	case latest.ActivatesAt.After(now):
+		// The next key is already scheduled.
+	case latest.Algorithm != cfg.jwtAlgorithm || !latest.ActivatesAt.After(now.Add(-jwtKeyRotationPeriod+jwtKeyPrepublish)):
+		if err := cfg.createJWTKey(ctx, qtx, now.Add(jwtKeyPrepublish)); err != nil {
+			return err
+		}
+	}
+
+	if err := qtx.DeleteRetiredJWTKeys(ctx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// latestReadableJWTKey returns the newest of rows, which are oldest first,
+// that can be decrypted with the current secret, unless an unreadable newer
+// key has already retired it.
+func (cfg *apiConfig) latestReadableJWTKey(rows []database.JwtKey) (database.JwtKey, bool) {
+	for i := len(rows) - 1; i >= 0; i-- {
+		if _, err := auth.DecryptPrivateKey(rows[i].PrivateKey, cfg.jwtSecret); err == nil {
+			return rows[i], !rows[i].RetiresAt.Valid
+		}
+	}
+	return database.JwtKey{}, false
+}
+
+// createJWTKey adds a key that starts signing at activatesAt, and retires
+// the keys before it once it has been signing for jwtKeyRetireAfter.
+func (cfg *apiConfig) createJWTKey(ctx context.Context, q *database.Queries, activatesAt time.Time) error {
+	private, err := auth.GenerateSigningKey(cfg.jwtAlgorithm)
+	if err != nil {
+		return err
+	}
+	sealed, err := auth.EncryptPrivateKey(private, cfg.jwtSecret)
+	if err != nil {
+		return err
+	}
+
+	key, err := q.CreateJWTKey(ctx, database.CreateJWTKeyParams{
+		ID:          uuid.NewString(),
+		Algorithm:   cfg.jwtAlgorithm,
+		PrivateKey:  sealed,
+		ActivatesAt: activatesAt,
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("Created %s JWT key %s, signing from %s", key.Algorithm, key.ID, key.ActivatesAt.Format(time.RFC3339))
+
+	return q.RetireJWTKeys(ctx, database.RetireJWTKeysParams{
+		RetiresAt: activatesAt.Add(jwtKeyRetireAfter),
+		ExceptID:  key.ID,
+	})
+}
+
+// refreshJWTKeys periodically rotates and reloads the keyring, picking up
+// keys that other instances created.
+func (cfg *apiConfig) refreshJWTKeys(ctx context.Context) {
+	ticker := time.NewTicker(jwtKeyRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := cfg.rotateJWTKeys(ctx); err != nil {
+				log.Printf("Couldn't rotate JWT keys: %v", err)
+			}
+			if err := cfg.reloadJWTKeys(ctx); err != nil {
+				log.Printf("Couldn't reload JWT keys: %v", err)
+			}
+		}
+	}
+}
+
+// handlerJWKS publishes the public keys that verify Chirpy access tokens.
+func (cfg *apiConfig) handlerJWKS(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	respondWithJSON(w, http.StatusOK, cfg.jwtKeys().JWKS())
+}
@@ -1,23 +1,35 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"net/http"
 	"os"
 	"sync/atomic"
 
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/Tanay-Verma/chirpy/internal/blobstore"
 	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/Tanay-Verma/chirpy/internal/profanity"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
 
 type apiConfig struct {
-	db             *database.Queries
-	platform       string
-	jwtSecret      string
-	polkaKey       string
-	fileserverHits atomic.Int32
+	db              *database.Queries
+	dbConn          *sql.DB
+	platform        string
+	jwtSecret       string
+	jwtAlgorithm    string
+	polkaKey        string
+	adminKey        string
+	fileserverHits  atomic.Int32
+	chirpsPurged    atomic.Int64
+	restrictedWords atomic.Pointer[profanity.Filter]
+	signingKeys     atomic.Pointer[auth.Keyring]
+	blobs           blobstore.BlobStore
+	chirpStream     *chirpStream
 }
 
 func main() {
@@ -33,16 +45,37 @@ func main() {
 		log.Fatal("DB_URL must be set")
 	}
 
+	// JWT_SECRET no longer signs tokens; it encrypts the signing keys kept
+	// in the database.
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
 		log.Fatal("JWT_SECRET must be set")
 	}
 
+	jwtAlgorithm := os.Getenv("JWT_ALGORITHM")
+	if jwtAlgorithm == "" {
+		jwtAlgorithm = auth.AlgorithmEdDSA
+	}
+	if jwtAlgorithm != auth.AlgorithmEdDSA && jwtAlgorithm != auth.AlgorithmRS256 {
+		log.Fatalf("JWT_ALGORITHM must be %s or %s", auth.AlgorithmEdDSA, auth.AlgorithmRS256)
+	}
+
 	polkaKey := os.Getenv("POLKA_KEY")
 	if polkaKey == "" {
 		log.Fatal("POLKA_KEY must be set")
 	}
 
+	adminKey := os.Getenv("ADMIN_KEY")
+
+	mediaRoot := os.Getenv("MEDIA_ROOT")
+	if mediaRoot == "" {
+		mediaRoot = "./media"
+	}
+	blobs, err := blobstore.NewLocal(mediaRoot)
+	if err != nil {
+		log.Fatalf("Error opening media storage: %v", err)
+	}
+
 	db, err := sql.Open("postgres", dbURL)
 	if err != nil {
 		log.Fatalf("Error connecting to database: %v", err)
@@ -56,10 +89,33 @@ func main() {
 	config := apiConfig{
 		fileserverHits: atomic.Int32{},
 		db:             dbQueries,
+		dbConn:         db,
 		platform:       platform,
 		jwtSecret:      jwtSecret,
+		jwtAlgorithm:   jwtAlgorithm,
 		polkaKey:       polkaKey,
+		adminKey:       adminKey,
+		blobs:          blobs,
+		chirpStream:    newChirpStream(),
+	}
+
+	if err := config.rotateJWTKeys(context.Background()); err != nil {
+		log.Fatalf("Error setting up JWT keys: %v", err)
+	}
+	if err := config.reloadJWTKeys(context.Background()); err != nil {
+		log.Fatalf("Error loading JWT keys: %v", err)
+	}
+	go config.refreshJWTKeys(context.Background())
+
+	if err := config.reloadProfanityFilter(context.Background()); err != nil {
+		log.Printf("Couldn't load restricted words, using defaults: %v", err)
 	}
+	go config.refreshProfanityFilter(context.Background())
+	go config.sweepStaleMedia(context.Background())
+	go config.finalizePolls(context.Background())
+	go config.publishScheduledDrafts(context.Background())
+	go config.purgeExpiredChirps(context.Background())
+	go config.listenForChirps(context.Background(), dbURL)
 
 	mux := http.NewServeMux()
 	mux.Handle(
@@ -68,23 +124,81 @@ func main() {
 	)
 
 	mux.HandleFunc("GET /api/healthz", handlerReadiness)
+	mux.HandleFunc("GET /.well-known/jwks.json", config.handlerJWKS)
 
 	mux.HandleFunc("POST /api/users", config.handlerCreateUser)
 	mux.HandleFunc("PUT /api/users", config.handlerUpdateUser)
+	mux.HandleFunc("POST /api/users/{userID}/follow", config.handlerFollowUser)
+	mux.HandleFunc("DELETE /api/users/{userID}/follow", config.handlerUnfollowUser)
+	mux.HandleFunc("POST /api/users/{userID}/block", config.handlerBlockUser)
+	mux.HandleFunc("DELETE /api/users/{userID}/block", config.handlerUnblockUser)
+	mux.HandleFunc("POST /api/users/{userID}/mute", config.handlerMuteUser)
+	mux.HandleFunc("DELETE /api/users/{userID}/mute", config.handlerUnmuteUser)
+	mux.HandleFunc("GET /api/muted-keywords", config.handlerGetMutedKeywords)
+	mux.HandleFunc("POST /api/muted-keywords", config.handlerCreateMutedKeyword)
+	mux.HandleFunc("DELETE /api/muted-keywords/{keywordID}", config.handlerDeleteMutedKeyword)
+	mux.HandleFunc("GET /api/notifications", config.handlerGetNotifications)
+	mux.HandleFunc("POST /api/notifications/read", config.handlerReadAllNotifications)
+	mux.HandleFunc("POST /api/notifications/{notificationID}/read", config.handlerReadNotification)
+	mux.HandleFunc("GET /api/users/{userID}/followers", config.handlerGetFollowers)
+	mux.HandleFunc("GET /api/users/{userID}/following", config.handlerGetFollowing)
+	mux.HandleFunc("GET /api/users/{userID}/mentions", config.handlerGetUserMentions)
 
 	mux.HandleFunc("POST /api/login", config.handlerLogin)
 	mux.HandleFunc("POST /api/refresh", config.handlerRefresh)
 	mux.HandleFunc("POST /api/revoke", config.handlerRevoke)
+	mux.HandleFunc("GET /api/sessions", config.handlerGetSessions)
+	mux.HandleFunc("DELETE /api/sessions/{sessionID}", config.handlerRevokeSession)
+	mux.HandleFunc("POST /api/sessions/revoke-all", config.handlerRevokeAllSessions)
 
 	mux.HandleFunc("POST /api/chirps", config.handlerCreateChirp)
 	mux.HandleFunc("GET /api/chirps", config.handlerGetChirps)
 	mux.HandleFunc("GET /api/chirps/{chirpID}", config.handlerGetChirp)
+	mux.HandleFunc("PUT /api/chirps/{chirpID}", config.handlerUpdateChirp)
+	mux.HandleFunc("PATCH /api/chirps/{chirpID}", config.handlerUpdateChirp)
 	mux.HandleFunc("DELETE /api/chirps/{chirpID}", config.handlerDeleteChirp)
+	mux.HandleFunc("GET /api/chirps/{chirpID}/revisions", config.handlerGetChirpRevisions)
+	mux.HandleFunc("GET /api/chirps/{chirpID}/thread", config.handlerGetChirpThread)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/likes", config.handlerLikeChirp)
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/likes", config.handlerUnlikeChirp)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/reactions", config.handlerAddReaction)
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/reactions/{emoji}", config.handlerRemoveReaction)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/poll/votes", config.handlerVotePoll)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/bookmark", config.handlerBookmarkChirp)
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/bookmark", config.handlerUnbookmarkChirp)
+	mux.HandleFunc("POST /api/chirps/{chirpID}/pin", config.handlerPinChirp)
+	mux.HandleFunc("DELETE /api/chirps/{chirpID}/pin", config.handlerUnpinChirp)
+
+	mux.HandleFunc("POST /api/drafts", config.handlerCreateDraft)
+	mux.HandleFunc("GET /api/drafts", config.handlerGetDrafts)
+	mux.HandleFunc("GET /api/drafts/{draftID}", config.handlerGetDraft)
+	mux.HandleFunc("PUT /api/drafts/{draftID}", config.handlerUpdateDraft)
+	mux.HandleFunc("DELETE /api/drafts/{draftID}", config.handlerDeleteDraft)
+
+	mux.HandleFunc("POST /api/conversations", config.handlerCreateConversation)
+	mux.HandleFunc("GET /api/conversations", config.handlerGetConversations)
+	mux.HandleFunc("GET /api/conversations/{conversationID}", config.handlerGetConversation)
+	mux.HandleFunc("POST /api/conversations/{conversationID}/messages", config.handlerSendMessage)
+	mux.HandleFunc("GET /api/conversations/{conversationID}/messages", config.handlerGetMessages)
+	mux.HandleFunc("POST /api/conversations/{conversationID}/read", config.handlerReadConversation)
+	mux.HandleFunc("POST /api/conversations/{conversationID}/leave", config.handlerLeaveConversation)
+
+	mux.HandleFunc("POST /api/media", config.handlerUploadMedia)
+	mux.HandleFunc("GET /media/{key}", config.handlerGetMedia)
+
+	mux.HandleFunc("GET /api/timeline", config.handlerGetTimeline)
+	mux.HandleFunc("GET /api/bookmarks", config.handlerGetBookmarks)
+	mux.HandleFunc("GET /api/hashtags/{tag}/chirps", config.handlerGetHashtagChirps)
+	mux.HandleFunc("GET /api/search", config.handlerSearch)
+	mux.HandleFunc("GET /api/stream", config.handlerStreamChirps)
 
 	mux.HandleFunc("POST /api/polka/webhooks", config.handlerPolkaWebhooks)
 
 	mux.HandleFunc("GET /admin/metrics", config.handlerMetrics)
 	mux.HandleFunc("POST /admin/reset", config.handlerReset)
+	mux.HandleFunc("GET /admin/restricted-words", config.handlerGetRestrictedWords)
+	mux.HandleFunc("POST /admin/restricted-words", config.handlerCreateRestrictedWord)
+	mux.HandleFunc("DELETE /admin/restricted-words/{wordID}", config.handlerDeleteRestrictedWord)
 
 	server := http.Server{
 		Handler: mux,
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/Tanay-Verma/chirpy/internal/media"
+	"github.com/google/uuid"
+)
+
+const (
+	maxMediaPerChirp    = 4
+	maxMediaUploadBytes = 5 << 20
+	// maxMultipartMemory is how much of a form is buffered in memory before
+	// file parts spill to disk.
+	maxMultipartMemory = 8 << 20
+
+	// Uploads that haven't been attached to a chirp after staleMediaAge are
+	// assumed abandoned.
+	staleMediaAge           = 24 * time.Hour
+	staleMediaSweepInterval = time.Hour
+)
+
+var (
+	errMediaTooLarge = fmt.Errorf("file is larger than %d bytes", maxMediaUploadBytes)
+	errInvalidImage  = errors.New("invalid image")
+)
+
+type MediaAttachment struct {
+	ID           uuid.UUID `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	Width        int32     `json:"width"`
+	Height       int32     `json:"height"`
+}
+
+func mediaAttachmentFromDB(attachment database.MediaAttachment) MediaAttachment {
+	return MediaAttachment{
+		ID:           attachment.ID,
+		CreatedAt:    attachment.CreatedAt,
+		URL:          "/media/" + attachment.StorageKey,
+		ThumbnailURL: "/media/" + attachment.ThumbnailKey,
+		ContentType:  attachment.ContentType,
+		Width:        attachment.Width,
+		Height:       attachment.Height,
+	}
+}
+
+// readUpload reads one uploaded file, refusing anything over the size limit.
+func readUpload(header *multipart.FileHeader) ([]byte, error) {
+	if header.Size > maxMediaUploadBytes {
+		return nil, errMediaTooLarge
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxMediaUploadBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxMediaUploadBytes {
+		return nil, errMediaTooLarge
+	}
+	return data, nil
+}
+
+// storeUpload processes an uploaded image and saves it with its thumbnail.
+// The attachment isn't linked to a chirp until AttachMedia.
+func (cfg *apiConfig) storeUpload(ctx context.Context, userID uuid.UUID, header *multipart.FileHeader) (database.MediaAttachment, error) {
+	data, err := readUpload(header)
+	if err != nil {
+		return database.MediaAttachment{}, err
+	}
+
+	img, err := media.Process(data)
+	if err != nil {
+		return database.MediaAttachment{}, fmt.Errorf("%w: %w", errInvalidImage, err)
+	}
+
+	name := uuid.NewString()
+	storageKey := name + img.Extension
+	thumbnailKey := name + "_thumb" + img.ThumbnailExtension
+
+	if err := cfg.blobs.Put(ctx, storageKey, bytes.NewReader(img.Data)); err != nil {
+		return database.MediaAttachment{}, err
+	}
+	if err := cfg.blobs.Put(ctx, thumbnailKey, bytes.NewReader(img.Thumbnail)); err != nil {
+		cfg.deleteBlobs(ctx, storageKey)
+		return database.MediaAttachment{}, err
+	}
+
+	attachment, err := cfg.db.CreateMediaAttachment(ctx, database.CreateMediaAttachmentParams{
+		UserID:       userID,
+		ContentType:  img.ContentType,
+		Width:        int32(img.Width),
+		Height:       int32(img.Height),
+		SizeBytes:    int64(len(img.Data)),
+		StorageKey:   storageKey,
+		ThumbnailKey: thumbnailKey,
+	})
+	if err != nil {
+		cfg.deleteBlobs(ctx, storageKey, thumbnailKey)
+		return database.MediaAttachment{}, err
+	}
+	return attachment, nil
+}
+
+// deleteChirpMedia removes a chirp's attachments. Pass a transaction-bound q;
+// the returned keys should only be deleted from the blob store once it has
+// committed.
+func deleteChirpMedia(ctx context.Context, q *database.Queries, chirpID uuid.UUID) ([]string, error) {
+	rows, err := q.DeleteChirpMedia(ctx, chirpID)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, 2*len(rows))
+	for _, row := range rows {
+		keys = append(keys, row.StorageKey, row.ThumbnailKey)
+	}
+	return keys, nil
+}
+
+// deleteBlobs removes blobs on a best-effort basis; a leftover file is
+// harmless once nothing references it.
+func (cfg *apiConfig) deleteBlobs(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := cfg.blobs.Delete(ctx, key); err != nil {
+			log.Printf("Couldn't delete blob %s: %v", key, err)
+		}
+	}
+}
+
+// uploadErrorStatus maps a failed upload to the status code and message the
+// client should see.
+func uploadErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, errMediaTooLarge):
+		return http.StatusRequestEntityTooLarge, "File is too large"
+	case errors.Is(err, media.ErrUnsupportedType):
+		return http.StatusUnsupportedMediaType, "Only JPEG, PNG and GIF images are supported"
+	case errors.Is(err, media.ErrTooManyPixels):
+		return http.StatusBadRequest, "Image dimensions are too large"
+	case errors.Is(err, media.ErrTooManyFrames):
+		return http.StatusBadRequest, fmt.Sprintf("Animations can have at most %d frames", media.MaxFrames)
+	case errors.Is(err, errInvalidImage):
+		return http.StatusBadRequest, "Couldn't decode the image"
+	}
+	return http.StatusInternalServerError, "Couldn't store the upload"
+}
+
+// sweepStaleMedia periodically removes uploads that were never attached to a
+// chirp, along with their blobs.
+func (cfg *apiConfig) sweepStaleMedia(ctx context.Context) {
+	ticker := time.NewTicker(staleMediaSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			rows, err := cfg.db.DeleteStaleMedia(ctx, time.Now().UTC().Add(-staleMediaAge))
+			if err != nil {
+				log.Printf("Couldn't sweep stale media: %v", err)
+				continue
+			}
+			for _, row := range rows {
+				cfg.deleteBlobs(ctx, row.StorageKey, row.ThumbnailKey)
+			}
+		}
+	}
+}
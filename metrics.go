@@ -19,6 +19,7 @@ func (cfg *apiConfig) handlerMetrics(w http.ResponseWriter, r *http.Request) {
   <body>
     <h1>Welcome, Chirpy Admin</h1>
     <p>Chirpy has been visited %d times!</p>
+    <p>%d expired chirps have been purged.</p>
   </body>
-</html>`, cfg.fileserverHits.Load())))
+</html>`, cfg.fileserverHits.Load(), cfg.chirpsPurged.Load())))
 }
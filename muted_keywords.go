@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/Tanay-Verma/chirpy/internal/profanity"
+	"github.com/google/uuid"
+	"github.com/rivo/uniseg"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	maxMutedKeywords        = 100
+	maxMutedKeywordLength   = 100
+	maxMutedKeywordLifetime = 365 * 24 * time.Hour
+)
+
+type MutedKeyword struct {
+	ID        uuid.UUID  `json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	Phrase    string     `json:"phrase"`
+	WholeWord bool       `json:"whole_word"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func mutedKeywordFromDB(keyword database.MutedKeyword) MutedKeyword {
+	response := MutedKeyword{
+		ID:        keyword.ID,
+		CreatedAt: keyword.CreatedAt,
+		Phrase:    keyword.Phrase,
+		WholeWord: keyword.WholeWord,
+	}
+	if keyword.ExpiresAt.Valid {
+		response.ExpiresAt = &keyword.ExpiresAt.Time
+	}
+	return response
+}
+
+// validateMutedPhrase normalizes a phrase to mute and checks that it has at
+// least one word in it to match on. Matching ignores case, so phrases are
+// stored lowercased and "Covid" and "covid" are the same keyword.
+func validateMutedPhrase(phrase string) (string, error) {
+	phrase = strings.TrimSpace(norm.NFC.String(strings.ToLower(phrase)))
+	if len(keywordWords(phrase)) == 0 {
+		return "", errors.New("A muted keyword needs at least one word")
+	}
+	if uniseg.GraphemeClusterCount(phrase) > maxMutedKeywordLength {
+		return "", fmt.Errorf("A muted keyword can be at most %d characters", maxMutedKeywordLength)
+	}
+	return phrase, nil
+}
+
+// mutedKeywordExpiresAt checks a requested expires_in, in seconds, and
+// returns when a keyword muted at now stops applying. nil means never.
+func mutedKeywordExpiresAt(now time.Time, expiresIn *int) (sql.NullTime, error) {
+	if expiresIn == nil {
+		return sql.NullTime{}, nil
+	}
+	if *expiresIn <= 0 || *expiresIn > int(maxMutedKeywordLifetime.Seconds()) {
+		return sql.NullTime{}, fmt.Errorf("expires_in must be between 1 and %d seconds", int(maxMutedKeywordLifetime.Seconds()))
+	}
+	return sql.NullTime{Time: now.UTC().Add(time.Duration(*expiresIn) * time.Second), Valid: true}, nil
+}
+
+// keywordMatcher finds a user's muted keywords in chirps. Text is split into
+// words with the profanity filter's tokenizer and lowercased, so punctuation
+// and spacing between the words of a phrase don't matter. Chirps are also
+// checked with their leetspeak undone, so muting "covid" catches "C0VID";
+// keywords are taken as written, so muting "4" doesn't catch "a".
+type keywordMatcher struct {
+	wholeWords []string
+	substrings []string
+}
+
+func newKeywordMatcher(keywords []database.MutedKeyword) *keywordMatcher {
+	m := &keywordMatcher{}
+	for _, keyword := range keywords {
+		words := strings.Join(keywordWords(keyword.Phrase), " ")
+		if words == "" {
+			continue
+		}
+		if keyword.WholeWord {
+			m.wholeWords = append(m.wholeWords, " "+words+" ")
+		} else {
+			m.substrings = append(m.substrings, words)
+		}
+	}
+	return m
+}
+
+// matches reports whether any of texts contains a muted keyword. A whole-word
+// keyword has to line up with word boundaries; any other keyword matches
+// inside longer words too.
+func (m *keywordMatcher) matches(texts ...string) bool {
+	if len(m.wholeWords) == 0 && len(m.substrings) == 0 {
+		return false
+	}
+
+	for _, text := range texts {
+		lowered := keywordWords(text)
+		for _, words := range [][]string{lowered, withoutLeetspeak(lowered)} {
+			joined := " " + strings.Join(words, " ") + " "
+			for _, keyword := range m.wholeWords {
+				if strings.Contains(joined, keyword) {
+					return true
+				}
+			}
+			for _, keyword := range m.substrings {
+				if strings.Contains(joined, keyword) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// keywordWords splits text into lowercased words.
+func keywordWords(text string) []string {
+	words := []string{}
+	for _, token := range profanity.Tokenize(text) {
+		if token.IsWord {
+			words = append(words, strings.ToLower(token.Text))
+		}
+	}
+	return words
+}
+
+// withoutLeetspeak undoes leetspeak in words, leaving numbers such as "50"
+// alone.
+func withoutLeetspeak(words []string) []string {
+	normalized := make([]string, len(words))
+	for i, word := range words {
+		if isNumber(word) {
+			normalized[i] = word
+		} else {
+			normalized[i] = profanity.Normalize(word)
+		}
+	}
+	return normalized
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutMutedKeywords drops the chirps that mention one of the viewer's
+// muted keywords, in their body, content warning or the chirp they share.
+// The viewer's own chirps are always kept.
+// Pages can come back short; their cursors were set before filtering, so
+// paging carries on from the right place.
+func (cfg *apiConfig) withoutMutedKeywords(ctx context.Context, viewerID uuid.UUID, chirps []Chirp) ([]Chirp, error) {
+	if viewerID == uuid.Nil || len(chirps) == 0 {
+		return chirps, nil
+	}
+
+	keywords, err := cfg.db.ListMutedKeywords(ctx, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(keywords) == 0 {
+		return chirps, nil
+	}
+
+	matcher := newKeywordMatcher(keywords)
+	filtered := make([]Chirp, 0, len(chirps))
+	for _, chirp := range chirps {
+		if chirp.UserID == viewerID {
+			filtered = append(filtered, chirp)
+			continue
+		}
+		texts := []string{chirp.Body, chirp.ContentWarning}
+		for _, embedded := range []*Chirp{chirp.RechirpOf, chirp.QuotedChirp} {
+			if embedded != nil {
+				texts = append(texts, embedded.Body, embedded.ContentWarning)
+			}
+		}
+		if !matcher.matches(texts...) {
+			filtered = append(filtered, chirp)
+		}
+	}
+	return filtered, nil
+}
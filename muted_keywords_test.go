@@ -0,0 +1,134 @@
+package main
+
+import (
+	"database/sql"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+)
+
+func TestKeywordMatcher(t *testing.T) {
+	matcher := newKeywordMatcher([]database.MutedKeyword{
+		{Phrase: "covid", WholeWord: true},
+		{Phrase: "season finale", WholeWord: true},
+		{Phrase: "spoil", WholeWord: false},
+		{Phrase: "4", WholeWord: true},
+		{Phrase: "1", WholeWord: true},
+		{Phrase: "50", WholeWord: false},
+		{Phrase: "h4x0r", WholeWord: true},
+	})
+
+	cases := []struct {
+		name  string
+		texts []string
+		want  bool
+	}{
+		{name: "whole word", texts: []string{"Another covid wave"}, want: true},
+		{name: "case and leet", texts: []string{"C0VID again"}, want: true},
+		{name: "punctuation around word", texts: []string{"(covid!)"}, want: true},
+		{name: "whole word inside longer word", texts: []string{"covidiots everywhere"}, want: false},
+		{name: "phrase", texts: []string{"Watching the season finale tonight"}, want: true},
+		{name: "phrase across punctuation", texts: []string{"season... finale"}, want: true},
+		{name: "phrase words apart", texts: []string{"season one finale"}, want: false},
+		{name: "substring", texts: []string{"No spoilers please"}, want: true},
+		{name: "later text", texts: []string{"nothing here", "covid"}, want: true},
+		{name: "no match", texts: []string{"Just a normal day", ""}, want: false},
+		{name: "digit keyword against letter", texts: []string{"I want a coffee"}, want: false},
+		{name: "digit keyword against I", texts: []string{"I am here"}, want: false},
+		{name: "digit substring against letters", texts: []string{"also fine"}, want: false},
+		{name: "digit keyword", texts: []string{"Top 4 picks"}, want: true},
+		{name: "digit substring", texts: []string{"Down 50%"}, want: true},
+		{name: "leet keyword as written", texts: []string{"a real H4X0R"}, want: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := matcher.matches(c.texts...); got != c.want {
+				t.Errorf("matches(%q) = %v, want %v", c.texts, got, c.want)
+			}
+		})
+	}
+}
+
+func TestKeywordMatcherEmpty(t *testing.T) {
+	if newKeywordMatcher(nil).matches("anything at all") {
+		t.Error("an empty matcher shouldn't match")
+	}
+}
+
+func TestValidateMutedPhrase(t *testing.T) {
+	cases := []struct {
+		name    string
+		phrase  string
+		want    string
+		wantErr bool
+	}{
+		{name: "trimmed", phrase: "  covid  ", want: "covid"},
+		{name: "phrase", phrase: "season finale", want: "season finale"},
+		{name: "composed", phrase: "café", want: "café"},
+		{name: "empty", phrase: "   ", wantErr: true},
+		{name: "punctuation only", phrase: "?!...", wantErr: true},
+		{name: "at limit", phrase: strings.Repeat("a", maxMutedKeywordLength), want: strings.Repeat("a", maxMutedKeywordLength)},
+		{name: "too long", phrase: strings.Repeat("a", maxMutedKeywordLength+1), wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := validateMutedPhrase(c.phrase)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Errorf("got %q, want %q", got, c.want)
+			}
+		})
+	}
+}
+
+func TestMutedKeywordExpiresAt(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	seconds := func(n int) *int { return &n }
+	maxSeconds := int(maxMutedKeywordLifetime.Seconds())
+
+	cases := []struct {
+		name      string
+		expiresIn *int
+		want      sql.NullTime
+		wantErr   bool
+	}{
+		{name: "never", expiresIn: nil},
+		{name: "an hour", expiresIn: seconds(3600), want: sql.NullTime{Time: now.Add(time.Hour), Valid: true}},
+		{name: "at limit", expiresIn: seconds(maxSeconds), want: sql.NullTime{Time: now.Add(maxMutedKeywordLifetime), Valid: true}},
+		{name: "zero", expiresIn: seconds(0), wantErr: true},
+		{name: "negative", expiresIn: seconds(-1), wantErr: true},
+		{name: "over limit", expiresIn: seconds(maxSeconds + 1), wantErr: true},
+		{name: "would overflow", expiresIn: seconds(math.MaxInt), wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := mutedKeywordExpiresAt(now, c.expiresIn)
+			if c.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != c.want {
+				t.Errorf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
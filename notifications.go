@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	notificationReply   = "reply"
+	notificationMention = "mention"
+	notificationFollow  = "follow"
+	notificationLike    = "like"
+
+	// notificationActorsShown caps how many of a grouped notification's
+	// actors are listed; actor_count still has the full number.
+	notificationActorsShown = 3
+)
+
+type Notification struct {
+	ID         uuid.UUID   `json:"id"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+	Kind       string      `json:"kind"`
+	ChirpID    *uuid.UUID  `json:"chirp_id,omitempty"`
+	ActorIDs   []uuid.UUID `json:"actor_ids"`
+	ActorCount int64       `json:"actor_count"`
+	Read       bool        `json:"read"`
+}
+
+// notificationEvent is something a user did that another user should hear
+// about. ChirpID is the chirp it happened on: the reply or mentioning chirp
+// itself, or the chirp that was liked.
+type notificationEvent struct {
+	Kind        string
+	RecipientID uuid.UUID
+	ActorID     uuid.UUID
+	ChirpID     uuid.NullUUID
+}
+
+// groupKey decides which events share a notification while it's unread.
+// Likes of the same chirp and new followers are grouped; every reply and
+// mention is its own chirp and so its own notification.
+func (e notificationEvent) groupKey() string {
+	if !e.ChirpID.Valid {
+		return e.Kind
+	}
+	return e.Kind + ":" + e.ChirpID.UUID.String()
+}
+
+// recordNotifications stores events as notifications, folding each into its
+// recipient's unread notification for the same group if there is one. Pass
+// the transaction-bound q of the action that caused them, so a notification
+// only exists if the action went through. Events a recipient shouldn't see
+// are dropped: their own actions, actions by users they blocked or muted,
+// and chirps they can't read.
+func recordNotifications(ctx context.Context, q *database.Queries, events ...notificationEvent) error {
+	for _, event := range events {
+		if event.RecipientID == event.ActorID {
+			continue
+		}
+
+		hidden, err := q.IsHiddenFrom(ctx, database.IsHiddenFromParams{
+			AuthorID: event.ActorID,
+			ViewerID: event.RecipientID,
+		})
+		if err != nil {
+			return err
+		}
+		if hidden {
+			continue
+		}
+
+		if event.ChirpID.Valid {
+			_, err := q.GetChirp(ctx, database.GetChirpParams{ID: event.ChirpID.UUID, ViewerID: event.RecipientID})
+			if errors.Is(err, sql.ErrNoRows) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		notification, err := q.UpsertNotification(ctx, database.UpsertNotificationParams{
+			UserID:   event.RecipientID,
+			Kind:     event.Kind,
+			ChirpID:  event.ChirpID,
+			GroupKey: event.groupKey(),
+		})
+		if err != nil {
+			return err
+		}
+
+		added, err := q.AddNotificationActor(ctx, database.AddNotificationActorParams{
+			NotificationID: notification.ID,
+			ActorID:        event.ActorID,
+		})
+		if err != nil {
+			return err
+		}
+		// Liking the same chirp twice, with an unlike in between, shouldn't
+		// push the notification back to the top.
+		if added > 0 {
+			if err := q.TouchNotification(ctx, notification.ID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// chirpNotifications works out who a newly published chirp notifies: the
+// author of the chirp it replies to, and everyone it mentions. Someone both
+// replied to and mentioned only hears about the reply.
+func chirpNotifications(ctx context.Context, q *database.Queries, chirp database.Chirp) ([]notificationEvent, error) {
+	events := []notificationEvent{}
+	notified := map[uuid.UUID]bool{}
+	chirpID := uuid.NullUUID{UUID: chirp.ID, Valid: true}
+
+	if chirp.InReplyToID.Valid {
+		parent, err := q.GetChirp(ctx, database.GetChirpParams{ID: chirp.InReplyToID.UUID, ViewerID: chirp.UserID})
+		if err != nil {
+			return nil, err
+		}
+		notified[parent.UserID] = true
+		events = append(events, notificationEvent{
+			Kind:        notificationReply,
+			RecipientID: parent.UserID,
+			ActorID:     chirp.UserID,
+			ChirpID:     chirpID,
+		})
+	}
+
+	entities, err := q.ListChirpEntities(ctx, []uuid.UUID{chirp.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, entity := range entities {
+		if !entity.MentionedUserID.Valid || notified[entity.MentionedUserID.UUID] {
+			continue
+		}
+		notified[entity.MentionedUserID.UUID] = true
+		events = append(events, notificationEvent{
+			Kind:        notificationMention,
+			RecipientID: entity.MentionedUserID.UUID,
+			ActorID:     chirp.UserID,
+			ChirpID:     chirpID,
+		})
+	}
+
+	return events, nil
+}
+
+// notificationResponses converts notifications, batching the actor lookups.
+func (cfg *apiConfig) notificationResponses(ctx context.Context, notifications []database.Notification) ([]Notification, error) {
+	response := make([]Notification, 0, len(notifications))
+	if len(notifications) == 0 {
+		return response, nil
+	}
+
+	notificationIDs := make([]uuid.UUID, len(notifications))
+	for i, notification := range notifications {
+		notificationIDs[i] = notification.ID
+	}
+
+	actors := map[uuid.UUID][]uuid.UUID{}
+	actorRows, err := cfg.db.ListRecentNotificationActors(ctx, database.ListRecentNotificationActorsParams{
+		NotificationIds: notificationIDs,
+		PerNotification: notificationActorsShown,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range actorRows {
+		actors[row.NotificationID] = append(actors[row.NotificationID], row.ActorID)
+	}
+
+	counts := map[uuid.UUID]int64{}
+	countRows, err := cfg.db.CountNotificationActors(ctx, notificationIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range countRows {
+		counts[row.NotificationID] = row.ActorCount
+	}
+
+	for _, notification := range notifications {
+		n := Notification{
+			ID:         notification.ID,
+			CreatedAt:  notification.CreatedAt,
+			UpdatedAt:  notification.UpdatedAt,
+			Kind:       notification.Kind,
+			ActorIDs:   []uuid.UUID{},
+			ActorCount: counts[notification.ID],
+			Read:       notification.ReadAt.Valid,
+		}
+		if notification.ChirpID.Valid {
+			n.ChirpID = &notification.ChirpID.UUID
+		}
+		if a, ok := actors[notification.ID]; ok {
+			n.ActorIDs = a
+		}
+		response = append(response, n)
+	}
+	return response, nil
+}
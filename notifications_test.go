@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNotificationGroupKey(t *testing.T) {
+	chirp := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	other := uuid.NullUUID{UUID: uuid.New(), Valid: true}
+	alice := uuid.New()
+	bob := uuid.New()
+
+	cases := []struct {
+		name      string
+		a, b      notificationEvent
+		wantGroup bool
+	}{
+		{
+			name:      "likes of the same chirp",
+			a:         notificationEvent{Kind: notificationLike, ActorID: alice, ChirpID: chirp},
+			b:         notificationEvent{Kind: notificationLike, ActorID: bob, ChirpID: chirp},
+			wantGroup: true,
+		},
+		{
+			name: "likes of different chirps",
+			a:    notificationEvent{Kind: notificationLike, ActorID: alice, ChirpID: chirp},
+			b:    notificationEvent{Kind: notificationLike, ActorID: alice, ChirpID: other},
+		},
+		{
+			name:      "follows",
+			a:         notificationEvent{Kind: notificationFollow, ActorID: alice},
+			b:         notificationEvent{Kind: notificationFollow, ActorID: bob},
+			wantGroup: true,
+		},
+		{
+			name: "different kinds on one chirp",
+			a:    notificationEvent{Kind: notificationReply, ActorID: alice, ChirpID: chirp},
+			b:    notificationEvent{Kind: notificationMention, ActorID: alice, ChirpID: chirp},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.a.groupKey() == c.b.groupKey(); got != c.wantGroup {
+				t.Errorf("grouped = %v, want %v (%q, %q)", got, c.wantGroup, c.a.groupKey(), c.b.groupKey())
+			}
+		})
+	}
+}
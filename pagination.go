@@ -0,0 +1,162 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+// pageCursor marks a position in a list ordered by (created_at, id), or an
+// offset for ranked lists that have no stable key. Clients only ever see it
+// encoded, so its layout can change without breaking them.
+type pageCursor struct {
+	CreatedAt time.Time `json:"t,omitempty"`
+	ID        uuid.UUID `json:"id,omitempty"`
+	Backward  bool      `json:"b,omitempty"`
+	Offset    int       `json:"o,omitempty"`
+}
+
+func encodeCursor(c pageCursor) string {
+	dat, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(dat)
+}
+
+func decodeCursor(s string) (pageCursor, error) {
+	dat, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return pageCursor{}, errors.New("malformed cursor")
+	}
+
+	c := pageCursor{}
+	if err := json.Unmarshal(dat, &c); err != nil {
+		return pageCursor{}, errors.New("malformed cursor")
+	}
+	if c.Offset < 0 || (c.Offset == 0 && (c.CreatedAt.IsZero() || c.ID == uuid.Nil)) {
+		return pageCursor{}, errors.New("malformed cursor")
+	}
+
+	return c, nil
+}
+
+// pageParams holds the limit and optional cursor of a paginated request.
+type pageParams struct {
+	Limit  int
+	Cursor *pageCursor
+}
+
+func parsePageParams(query url.Values) (pageParams, error) {
+	params := pageParams{Limit: defaultPageLimit}
+
+	if limitString := query.Get("limit"); limitString != "" {
+		limit, err := strconv.Atoi(limitString)
+		if err != nil || limit < 1 {
+			return pageParams{}, errors.New("Invalid limit")
+		}
+		params.Limit = min(limit, maxPageLimit)
+	}
+
+	if cursorString := query.Get("cursor"); cursorString != "" {
+		c, err := decodeCursor(cursorString)
+		if err != nil {
+			return pageParams{}, errors.New("Invalid cursor")
+		}
+		params.Cursor = &c
+	}
+
+	return params, nil
+}
+
+// parseForwardPageParams is parsePageParams for lists that can only be walked
+// newest first.
+func parseForwardPageParams(query url.Values) (pageParams, error) {
+	params, err := parsePageParams(query)
+	if err != nil {
+		return pageParams{}, err
+	}
+	if params.Cursor != nil && params.Cursor.Backward {
+		return pageParams{}, errors.New("Invalid cursor")
+	}
+	return params, nil
+}
+
+// cursorColumns returns the cursor position as nullable query arguments.
+func (p pageParams) cursorColumns() (sql.NullTime, uuid.NullUUID) {
+	if p.Cursor == nil {
+		return sql.NullTime{}, uuid.NullUUID{}
+	}
+	return sql.NullTime{Time: p.Cursor.CreatedAt, Valid: true},
+		uuid.NullUUID{UUID: p.Cursor.ID, Valid: true}
+}
+
+// paginate trims a page that was fetched with one extra row and works out the
+// cursors around it. Backward pages are fetched in reverse and flipped here,
+// so rows always come back in display order.
+func paginate[T any](rows []T, params pageParams, position func(T) pageCursor) (page []T, next, prev string) {
+	hasMore := len(rows) > params.Limit
+	if hasMore {
+		rows = rows[:params.Limit]
+	}
+
+	backward := params.Cursor != nil && params.Cursor.Backward
+	if backward {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	if len(rows) == 0 {
+		return rows, "", ""
+	}
+
+	if hasMore || backward {
+		c := position(rows[len(rows)-1])
+		next = encodeCursor(c)
+	}
+	if (backward && hasMore) || (!backward && params.Cursor != nil) {
+		c := position(rows[0])
+		c.Backward = true
+		prev = encodeCursor(c)
+	}
+
+	return rows, next, prev
+}
+
+// setPaginationHeaders advertises the neighbouring pages through a Link header
+// and plain cursor headers for clients that don't parse Link.
+func setPaginationHeaders(w http.ResponseWriter, req *http.Request, next, prev string) {
+	links := []string{}
+	if next != "" {
+		w.Header().Set("X-Next-Cursor", next)
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(req, next)))
+	}
+	if prev != "" {
+		w.Header().Set("X-Prev-Cursor", prev)
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(req, prev)))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+func pageURL(req *http.Request, cursor string) string {
+	query := req.URL.Query()
+	query.Set("cursor", cursor)
+	return req.URL.Path + "?" + query.Encode()
+}
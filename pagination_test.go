@@ -0,0 +1,104 @@
+package main
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := pageCursor{
+		CreatedAt: time.Date(2024, 11, 5, 10, 30, 0, 123456000, time.UTC),
+		ID:        uuid.New(),
+		Backward:  true,
+	}
+
+	got, err := decodeCursor(encodeCursor(want))
+	if err != nil {
+		t.Fatalf("decodeCursor() error = %v", err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID || got.Backward != want.Backward {
+		t.Errorf("decodeCursor() got = %+v, want = %+v", got, want)
+	}
+}
+
+func TestParsePageParams(t *testing.T) {
+	validCursor := encodeCursor(pageCursor{CreatedAt: time.Now(), ID: uuid.New()})
+
+	tests := []struct {
+		name      string
+		query     url.Values
+		wantLimit int
+		wantErr   bool
+	}{
+		{
+			name:      "Defaults",
+			query:     url.Values{},
+			wantLimit: defaultPageLimit,
+		},
+		{
+			name:      "Limit is capped",
+			query:     url.Values{"limit": []string{"1000"}},
+			wantLimit: maxPageLimit,
+		},
+		{
+			name:    "Zero limit",
+			query:   url.Values{"limit": []string{"0"}},
+			wantErr: true,
+		},
+		{
+			name:      "Valid cursor",
+			query:     url.Values{"cursor": []string{validCursor}},
+			wantLimit: defaultPageLimit,
+		},
+		{
+			name:    "Garbage cursor",
+			query:   url.Values{"cursor": []string{"not-a-cursor"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePageParams(tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parsePageParams() error = %v, wantErr = %v", err, tt.wantErr)
+				return
+			}
+			if got.Limit != tt.wantLimit {
+				t.Errorf("parsePageParams() limit = %v, want = %v", got.Limit, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestPaginate(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []pageCursor{}
+	for i := 0; i < 4; i++ {
+		rows = append(rows, pageCursor{CreatedAt: base.Add(time.Duration(i) * time.Minute), ID: uuid.New()})
+	}
+	position := func(c pageCursor) pageCursor { return c }
+
+	page, next, prev := paginate(append([]pageCursor{}, rows...), pageParams{Limit: 3}, position)
+	if len(page) != 3 || next == "" || prev != "" {
+		t.Fatalf("first page: len = %d, next = %q, prev = %q", len(page), next, prev)
+	}
+
+	nextCursor, _ := decodeCursor(next)
+	if nextCursor.ID != rows[2].ID || nextCursor.Backward {
+		t.Errorf("next cursor = %+v, want position of %v", nextCursor, rows[2].ID)
+	}
+
+	// A backward page arrives in reverse order and must be flipped back.
+	reversed := []pageCursor{rows[2], rows[1], rows[0]}
+	page, next, prev = paginate(reversed, pageParams{Limit: 2, Cursor: &pageCursor{Backward: true}}, position)
+	if len(page) != 2 || page[0].ID != rows[1].ID || page[1].ID != rows[2].ID {
+		t.Errorf("backward page out of order: %+v", page)
+	}
+	if next == "" || prev == "" {
+		t.Errorf("backward page: next = %q, prev = %q", next, prev)
+	}
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+	"github.com/rivo/uniseg"
+)
+
+const (
+	minPollOptions        = 2
+	maxPollOptions        = 4
+	maxPollOptionLength   = 25
+	minPollDuration       = 5 * time.Minute
+	maxPollDuration       = 7 * 24 * time.Hour
+	pollFinalizerInterval = time.Minute
+)
+
+type pollParams struct {
+	Options  []string  `json:"options"`
+	ClosesAt time.Time `json:"closes_at"`
+}
+
+type Poll struct {
+	Options     []PollOption `json:"options"`
+	ClosesAt    time.Time    `json:"closes_at"`
+	Closed      bool         `json:"closed"`
+	TotalVotes  *int32       `json:"total_votes,omitempty"`
+	VotedOption *int32       `json:"voted_option,omitempty"`
+}
+
+type PollOption struct {
+	Label string `json:"label"`
+	Votes *int32 `json:"votes,omitempty"`
+}
+
+// validatePoll checks a poll submitted with a new chirp and returns its
+// options trimmed of surrounding whitespace.
+func validatePoll(params pollParams, now time.Time) ([]string, error) {
+	if len(params.Options) < minPollOptions || len(params.Options) > maxPollOptions {
+		return nil, fmt.Errorf("A poll needs %d to %d options", minPollOptions, maxPollOptions)
+	}
+
+	options := make([]string, len(params.Options))
+	seen := map[string]bool{}
+	for i, option := range params.Options {
+		option = strings.TrimSpace(option)
+		if option == "" {
+			return nil, errors.New("Poll options can't be empty")
+		}
+		if uniseg.GraphemeClusterCount(option) > maxPollOptionLength {
+			return nil, fmt.Errorf("Poll options can be at most %d characters", maxPollOptionLength)
+		}
+		if seen[strings.ToLower(option)] {
+			return nil, errors.New("Poll options must be different")
+		}
+		seen[strings.ToLower(option)] = true
+		options[i] = option
+	}
+
+	duration := params.ClosesAt.Sub(now)
+	if duration < minPollDuration || duration > maxPollDuration {
+		return nil, fmt.Errorf("A poll must close between %v and %v from now", minPollDuration, maxPollDuration)
+	}
+
+	return options, nil
+}
+
+// createPoll stores a validated poll for a chirp. Pass a transaction-bound q
+// so the poll is created with its chirp.
+func createPoll(ctx context.Context, q *database.Queries, chirpID uuid.UUID, options []string, closesAt time.Time) error {
+	if err := q.CreatePoll(ctx, database.CreatePollParams{
+		ChirpID:  chirpID,
+		ClosesAt: closesAt.UTC(),
+	}); err != nil {
+		return err
+	}
+
+	for i, option := range options {
+		if err := q.CreatePollOption(ctx, database.CreatePollOptionParams{
+			ChirpID:  chirpID,
+			Position: int32(i),
+			Label:    option,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pollFromDB builds the poll a viewer sees. Tallies stay hidden until the
+// viewer has voted or the poll has closed, so early results can't sway the
+// vote. votedOption is nil if the viewer hasn't voted.
+func pollFromDB(poll database.Poll, options []database.PollOption, votedOption *int32, now time.Time) Poll {
+	response := Poll{
+		Options:     make([]PollOption, len(options)),
+		ClosesAt:    poll.ClosesAt,
+		Closed:      poll.FinalizedAt.Valid || !now.Before(poll.ClosesAt),
+		VotedOption: votedOption,
+	}
+
+	showTallies := response.Closed || votedOption != nil
+	total := int32(0)
+	for i, option := range options {
+		response.Options[i] = PollOption{Label: option.Label}
+		if showTallies {
+			votes := option.VoteCount
+			response.Options[i].Votes = &votes
+			total += votes
+		}
+	}
+	if showTallies {
+		response.TotalVotes = &total
+	}
+
+	return response
+}
+
+// finalizePolls periodically closes polls that have passed their closing
+// time, recounting their votes so the final tallies are exact.
+func (cfg *apiConfig) finalizePolls(ctx context.Context) {
+	ticker := time.NewTicker(pollFinalizerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := cfg.finalizeExpiredPolls(ctx); err != nil {
+				log.Printf("Couldn't finalize polls: %v", err)
+			}
+		}
+	}
+}
+
+func (cfg *apiConfig) finalizeExpiredPolls(ctx context.Context) ([]uuid.UUID, error) {
+	tx, err := cfg.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := cfg.db.WithTx(tx)
+
+	chirpIDs, err := qtx.FinalizeExpiredPolls(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(chirpIDs) == 0 {
+		return nil, nil
+	}
+
+	if err := qtx.RecountPollVotes(ctx, chirpIDs); err != nil {
+		return nil, err
+	}
+	return chirpIDs, tx.Commit()
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+)
+
+func TestValidatePoll(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tomorrow := now.Add(24 * time.Hour)
+
+	cases := []struct {
+		name    string
+		params  pollParams
+		wantErr bool
+	}{
+		{name: "valid", params: pollParams{Options: []string{" yes ", "no"}, ClosesAt: tomorrow}},
+		{name: "one option", params: pollParams{Options: []string{"yes"}, ClosesAt: tomorrow}, wantErr: true},
+		{name: "five options", params: pollParams{Options: []string{"a", "b", "c", "d", "e"}, ClosesAt: tomorrow}, wantErr: true},
+		{name: "empty option", params: pollParams{Options: []string{"yes", "  "}, ClosesAt: tomorrow}, wantErr: true},
+		{name: "duplicate options", params: pollParams{Options: []string{"Yes", "yes"}, ClosesAt: tomorrow}, wantErr: true},
+		{name: "long option", params: pollParams{Options: []string{"yes", "this option is far too long to fit"}, ClosesAt: tomorrow}, wantErr: true},
+		{name: "closes in the past", params: pollParams{Options: []string{"yes", "no"}, ClosesAt: now.Add(-time.Hour)}, wantErr: true},
+		{name: "closes too late", params: pollParams{Options: []string{"yes", "no"}, ClosesAt: now.Add(30 * 24 * time.Hour)}, wantErr: true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			options, err := validatePoll(c.params, now)
+			if (err != nil) != c.wantErr {
+				t.Fatalf("validatePoll() error = %v, wantErr %v", err, c.wantErr)
+			}
+			if err == nil && options[0] != "yes" {
+				t.Errorf("options not trimmed: %q", options)
+			}
+		})
+	}
+}
+
+func TestPollFromDBHidesTallies(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	options := []database.PollOption{
+		{Position: 0, Label: "yes", VoteCount: 3},
+		{Position: 1, Label: "no", VoteCount: 2},
+	}
+	open := database.Poll{ClosesAt: now.Add(time.Hour)}
+	voted := int32(1)
+
+	cases := []struct {
+		name        string
+		poll        database.Poll
+		votedOption *int32
+		wantTallies bool
+		wantClosed  bool
+	}{
+		{name: "open, not voted", poll: open},
+		{name: "open, voted", poll: open, votedOption: &voted, wantTallies: true},
+		{name: "past closing time", poll: database.Poll{ClosesAt: now.Add(-time.Minute)}, wantTallies: true, wantClosed: true},
+		{
+			name:        "finalized",
+			poll:        database.Poll{ClosesAt: now.Add(-time.Minute), FinalizedAt: sql.NullTime{Time: now, Valid: true}},
+			wantTallies: true,
+			wantClosed:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			poll := pollFromDB(c.poll, options, c.votedOption, now)
+			if poll.Closed != c.wantClosed {
+				t.Errorf("Closed = %v, want %v", poll.Closed, c.wantClosed)
+			}
+			if (poll.TotalVotes != nil) != c.wantTallies {
+				t.Fatalf("TotalVotes = %v, want tallies %v", poll.TotalVotes, c.wantTallies)
+			}
+			for _, option := range poll.Options {
+				if (option.Votes != nil) != c.wantTallies {
+					t.Errorf("option %q votes = %v, want tallies %v", option.Label, option.Votes, c.wantTallies)
+				}
+			}
+			if c.wantTallies && *poll.TotalVotes != 5 {
+				t.Errorf("TotalVotes = %d, want 5", *poll.TotalVotes)
+			}
+		})
+	}
+}
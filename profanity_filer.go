@@ -1,16 +1,67 @@
 package main
 
-import "strings"
+import (
+	"context"
+	"log"
+	"time"
 
-const replacementWord = "****"
+	"github.com/Tanay-Verma/chirpy/internal/profanity"
+)
 
-func getCleanedBody(message string, restrictedWords map[string]struct{}) string {
-	msgTokens := strings.Split(message, " ")
-	for i, word := range msgTokens {
-		if _, ok := restrictedWords[strings.ToLower(word)]; ok {
-			msgTokens[i] = replacementWord
+const profanityRefreshInterval = time.Minute
+
+// defaultRestrictedWords keeps chirps filtered if the word list can't be
+// loaded from the database.
+var defaultRestrictedWords = []string{"kerfuffle", "sharbert", "fornax"}
+
+// profanityFilter returns the filter currently in use.
+func (cfg *apiConfig) profanityFilter() *profanity.Filter {
+	if filter := cfg.restrictedWords.Load(); filter != nil {
+		return filter
+	}
+	filter, _ := profanity.New(defaultRestrictedWords)
+	return filter
+}
+
+// reloadProfanityFilter rebuilds the filter from the restricted_words table.
+// Rules that no longer compile are skipped rather than disabling the filter.
+func (cfg *apiConfig) reloadProfanityFilter(ctx context.Context) error {
+	words, err := cfg.db.ListRestrictedWords(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules := make([]string, 0, len(words))
+	for _, word := range words {
+		if err := profanity.ValidateRule(word.Pattern); err != nil {
+			log.Printf("Skipping restricted word %q: %v", word.Pattern, err)
+			continue
 		}
+		rules = append(rules, word.Pattern)
 	}
 
-	return strings.Join(msgTokens, " ")
+	filter, err := profanity.New(rules)
+	if err != nil {
+		return err
+	}
+	cfg.restrictedWords.Store(filter)
+	return nil
+}
+
+// refreshProfanityFilter periodically reloads the word list so edits made
+// through another instance are picked up without a restart.
+func (cfg *apiConfig) refreshProfanityFilter(ctx context.Context) {
+	ticker := time.NewTicker(profanityRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := cfg.reloadProfanityFilter(ctx); err != nil {
+				log.Printf("Couldn't reload restricted words: %v", err)
+			}
+		}
+	}
 }
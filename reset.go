@@ -10,6 +10,7 @@ func (cfg *apiConfig) handlerReset(w http.ResponseWriter, r *http.Request) {
 	}
 
 	cfg.fileserverHits.Store(0)
+	cfg.chirpsPurged.Store(0)
 	cfg.db.Reset(r.Context())
 
 	w.WriteHeader(http.StatusOK)
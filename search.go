@@ -0,0 +1,61 @@
+package main
+
+import (
+	"strings"
+)
+
+// searchQuery is a parsed /api/search query. Text keeps quoted phrases intact
+// for websearch_to_tsquery.
+type searchQuery struct {
+	Text    string
+	From    string
+	Hashtag string
+}
+
+func parseSearchQuery(q string) searchQuery {
+	query := searchQuery{}
+	text := []string{}
+	for _, token := range splitSearchTokens(q) {
+		switch {
+		case strings.HasPrefix(token, "from:") && len(token) > len("from:"):
+			query.From = strings.TrimPrefix(strings.TrimPrefix(token, "from:"), "@")
+		case strings.HasPrefix(token, "#") && len(token) > 1:
+			query.Hashtag = strings.TrimPrefix(token, "#")
+		default:
+			text = append(text, token)
+		}
+	}
+	query.Text = strings.Join(text, " ")
+	return query
+}
+
+// splitSearchTokens splits on whitespace outside of double quotes, keeping
+// the quotes on phrase tokens.
+func splitSearchTokens(q string) []string {
+	tokens := []string{}
+	current := strings.Builder{}
+	inPhrase := false
+	for _, r := range q {
+		switch {
+		case r == '"':
+			inPhrase = !inPhrase
+			current.WriteRune(r)
+		case !inPhrase && (r == ' ' || r == '\t' || r == '\n'):
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// escapeLike makes user input safe to use as a literal LIKE prefix.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  searchQuery
+	}{
+		{
+			name:  "Plain words",
+			query: "breakfast  opinions",
+			want:  searchQuery{Text: "breakfast opinions"},
+		},
+		{
+			name:  "Phrase keeps its spaces",
+			query: `"need to share" world`,
+			want:  searchQuery{Text: `"need to share" world`},
+		},
+		{
+			name:  "Operators",
+			query: "from:@boots #golang generics",
+			want:  searchQuery{Text: "generics", From: "boots", Hashtag: "golang"},
+		},
+		{
+			name:  "Bare operators are text",
+			query: "from: #",
+			want:  searchQuery{Text: "from: #"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSearchQuery(tt.query)
+			if got != tt.want {
+				t.Errorf("parseSearchQuery() got = %+v, want = %+v", got, tt.want)
+			}
+		})
+	}
+}
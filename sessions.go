@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Tanay-Verma/chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// maxUserAgentLength caps what's stored of a client's User-Agent header.
+const maxUserAgentLength = 256
+
+// Session is a login, as seen through the current refresh token of its
+// family. Its ID stays the same across refreshes.
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+}
+
+func sessionFromDB(token database.RefreshToken) Session {
+	return Session{
+		ID:         token.FamilyID,
+		CreatedAt:  token.SessionStartedAt,
+		LastUsedAt: token.LastUsedAt,
+		ExpiresAt:  token.ExpiresAt,
+		UserAgent:  token.UserAgent,
+		IP:         token.Ip,
+	}
+}
+
+// sessionClient describes the client a refresh token was issued to.
+type sessionClient struct {
+	UserAgent string
+	IP        string
+}
+
+func sessionClientFromRequest(req *http.Request) sessionClient {
+	return sessionClient{
+		UserAgent: truncateUserAgent(req.UserAgent()),
+		IP:        clientIP(req.RemoteAddr),
+	}
+}
+
+// clientIP strips the port from a request's RemoteAddr. Forwarding headers
+// are ignored since nothing guarantees a trusted proxy set them.
+func clientIP(remoteAddr string) string {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
+
+func truncateUserAgent(userAgent string) string {
+	if len(userAgent) <= maxUserAgentLength {
+		return userAgent
+	}
+	userAgent = userAgent[:maxUserAgentLength]
+	for !utf8.ValidString(userAgent) {
+		userAgent = userAgent[:len(userAgent)-1]
+	}
+	return userAgent
+}
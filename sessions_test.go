@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestClientIP(t *testing.T) {
+	cases := []struct {
+		remoteAddr string
+		want       string
+	}{
+		{remoteAddr: "203.0.113.7:51234", want: "203.0.113.7"},
+		{remoteAddr: "[2001:db8::1]:443", want: "2001:db8::1"},
+		{remoteAddr: "203.0.113.7", want: "203.0.113.7"},
+		{remoteAddr: "", want: ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.remoteAddr, func(t *testing.T) {
+			if got := clientIP(c.remoteAddr); got != c.want {
+				t.Errorf("clientIP(%q) = %q, want %q", c.remoteAddr, got, c.want)
+			}
+		})
+	}
+}
+
+func TestTruncateUserAgent(t *testing.T) {
+	cases := []struct {
+		name      string
+		userAgent string
+		wantLen   int
+	}{
+		{name: "short", userAgent: "curl/8.5.0", wantLen: len("curl/8.5.0")},
+		{name: "at limit", userAgent: strings.Repeat("a", maxUserAgentLength), wantLen: maxUserAgentLength},
+		{name: "too long", userAgent: strings.Repeat("a", maxUserAgentLength+10), wantLen: maxUserAgentLength},
+		{name: "cut inside a rune", userAgent: "a" + strings.Repeat("é", maxUserAgentLength), wantLen: maxUserAgentLength - 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := truncateUserAgent(c.userAgent)
+			if len(got) != c.wantLen || !utf8.ValidString(got) {
+				t.Errorf("got %d bytes (valid %v), want %d", len(got), utf8.ValidString(got), c.wantLen)
+			}
+		})
+	}
+}
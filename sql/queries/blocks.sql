@@ -0,0 +1,61 @@
+-- name: BlockUser :exec
+INSERT INTO blocks (
+  blocker_id,
+  blocked_id,
+  created_at
+) VALUES (
+  $1,
+  $2,
+  NOW()
+)
+ON CONFLICT DO NOTHING;
+
+-- name: UnblockUser :exec
+DELETE FROM blocks
+WHERE blocker_id = $1 AND blocked_id = $2;
+
+-- name: IsBlocked :one
+SELECT EXISTS (
+  SELECT 1
+  FROM blocks
+  WHERE blocker_id = sqlc.arg('blocker_id')::uuid
+  AND blocked_id = sqlc.arg('blocked_id')::uuid
+) AS blocked;
+
+-- name: IsBlockedByAny :one
+SELECT EXISTS (
+  SELECT 1
+  FROM blocks
+  WHERE blocker_id = ANY(sqlc.arg('blocker_ids')::uuid[])
+  AND blocked_id = sqlc.arg('blocked_id')::uuid
+) AS blocked;
+
+-- name: IsBlockedInConversation :one
+SELECT EXISTS (
+  SELECT 1
+  FROM blocks
+  JOIN conversation_members ON conversation_members.user_id = blocks.blocker_id
+  WHERE conversation_members.conversation_id = sqlc.arg('conversation_id')::uuid
+  AND blocks.blocked_id = sqlc.arg('user_id')::uuid
+) AS blocked;
+
+-- name: RemoveFollowsBetween :exec
+DELETE FROM follows
+WHERE (follower_id = sqlc.arg('user_id')::uuid AND followee_id = sqlc.arg('other_user_id')::uuid)
+OR (follower_id = sqlc.arg('other_user_id')::uuid AND followee_id = sqlc.arg('user_id')::uuid);
+
+-- name: MuteUser :exec
+INSERT INTO mutes (
+  muter_id,
+  muted_id,
+  created_at
+) VALUES (
+  $1,
+  $2,
+  NOW()
+)
+ON CONFLICT DO NOTHING;
+
+-- name: UnmuteUser :exec
+DELETE FROM mutes
+WHERE muter_id = $1 AND muted_id = $2;
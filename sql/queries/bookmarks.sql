@@ -0,0 +1,32 @@
+-- name: BookmarkChirp :exec
+INSERT INTO bookmarks (
+  user_id,
+  chirp_id,
+  created_at
+) VALUES (
+  $1,
+  $2,
+  NOW()
+)
+ON CONFLICT DO NOTHING;
+
+-- name: UnbookmarkChirp :exec
+DELETE FROM bookmarks
+WHERE user_id = $1 AND chirp_id = $2;
+
+-- name: ListBookmarks :many
+SELECT *
+FROM bookmarks
+WHERE user_id = sqlc.arg('user_id')
+AND (
+  sqlc.narg('before_created_at')::timestamp IS NULL
+  OR (created_at, chirp_id) < (sqlc.narg('before_created_at'), sqlc.narg('before_id')::uuid)
+)
+ORDER BY created_at DESC, chirp_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListBookmarkedChirpIDs :many
+SELECT chirp_id
+FROM bookmarks
+WHERE user_id = sqlc.arg('user_id')
+AND chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
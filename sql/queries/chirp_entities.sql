@@ -0,0 +1,30 @@
+-- name: CreateChirpEntity :exec
+INSERT INTO chirp_entities (
+  chirp_id,
+  kind,
+  value,
+  start_offset,
+  end_offset,
+  mentioned_user_id
+) VALUES (
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6
+);
+
+-- name: DeleteChirpEntities :exec
+DELETE FROM chirp_entities
+WHERE chirp_id = $1;
+
+-- name: DeleteEntitiesOfChirps :exec
+DELETE FROM chirp_entities
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
+
+-- name: ListChirpEntities :many
+SELECT *
+FROM chirp_entities
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+ORDER BY chirp_id, start_offset;
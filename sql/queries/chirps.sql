@@ -4,27 +4,260 @@ INSERT INTO chirps (
   created_at,
   updated_at,
   body,
-  user_id
+  user_id,
+  in_reply_to_id,
+  root_id,
+  rechirp_of_id,
+  quote_of_id,
+  visibility,
+  content_warning,
+  expires_at
 ) VALUES (
   gen_random_uuid(),
   NOW(),
   NOW(),
   $1,
-  $2
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9
 )
 RETURNING *;
 
--- name: GetChirps :many
+-- name: ListChirpsAsc :many
 SELECT *
 FROM chirps
-ORDER BY created_at ASC;
+WHERE deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+AND (sqlc.narg('author_id')::uuid IS NOT NULL OR visibility <> 'unlisted')
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg('viewer_id')::uuid)
+AND NOT chirp_hidden_from(user_id, sqlc.arg('viewer_id')::uuid)
+AND (
+  sqlc.narg('after_created_at')::timestamp IS NULL
+  OR (created_at, id) > (sqlc.narg('after_created_at'), sqlc.narg('after_id')::uuid)
+)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: ListChirpsDesc :many
+SELECT *
+FROM chirps
+WHERE deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+AND (sqlc.narg('author_id')::uuid IS NOT NULL OR visibility <> 'unlisted')
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg('viewer_id')::uuid)
+AND NOT chirp_hidden_from(user_id, sqlc.arg('viewer_id')::uuid)
+AND (
+  sqlc.narg('before_created_at')::timestamp IS NULL
+  OR (created_at, id) < (sqlc.narg('before_created_at'), sqlc.narg('before_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
 
 -- name: GetChirp :one
 SELECT *
 FROM chirps
-WHERE id = $1;
+WHERE id = sqlc.arg('id')
+AND deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg('viewer_id')::uuid);
+
+-- name: GetChirpForUpdate :one
+SELECT *
+FROM chirps
+WHERE id = $1 AND deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+FOR UPDATE;
+
+-- name: UpdateChirpBody :one
+UPDATE chirps
+SET body = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListChirpsByIDs :many
+SELECT *
+FROM chirps
+WHERE id = ANY(sqlc.arg('ids')::uuid[])
+AND (expires_at IS NULL OR expires_at > NOW())
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg('viewer_id')::uuid)
+AND NOT chirp_hidden_from(user_id, sqlc.arg('viewer_id')::uuid);
 
 -- name: DeleteChirp :exec
 DELETE FROM chirps
 WHERE id = $1 AND user_id = $2;
 
+-- name: TombstoneChirp :exec
+UPDATE chirps
+SET body = '', content_warning = NULL, deleted_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND user_id = $2;
+
+-- name: HasReplies :one
+SELECT EXISTS (
+  SELECT 1
+  FROM chirps
+  WHERE in_reply_to_id = sqlc.arg('chirp_id')::uuid
+) AS has_replies;
+
+-- name: ListThreadChirps :many
+SELECT *
+FROM chirps
+WHERE (id = sqlc.arg('root_id') OR root_id = sqlc.arg('root_id'))
+AND (expires_at IS NULL OR expires_at > NOW())
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg('viewer_id')::uuid)
+AND NOT chirp_hidden_from(user_id, sqlc.arg('viewer_id')::uuid)
+ORDER BY created_at ASC, id ASC;
+
+-- name: ListTimeline :many
+SELECT chirps.*
+FROM chirps
+JOIN follows ON follows.followee_id = chirps.user_id
+WHERE follows.follower_id = sqlc.arg('user_id')
+AND chirps.deleted_at IS NULL
+AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg('user_id')::uuid)
+AND NOT chirp_hidden_from(chirps.user_id, sqlc.arg('user_id')::uuid)
+AND (
+  sqlc.narg('before_created_at')::timestamp IS NULL
+  OR (chirps.created_at, chirps.id) < (sqlc.narg('before_created_at'), sqlc.narg('before_id')::uuid)
+)
+ORDER BY chirps.created_at DESC, chirps.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: AdjustLikeCount :exec
+UPDATE chirps
+SET like_count = GREATEST(like_count + sqlc.arg('delta')::integer, 0)
+WHERE id = sqlc.arg('id');
+
+-- name: AdjustReactionCount :exec
+UPDATE chirps
+SET reaction_counts = CASE
+  WHEN COALESCE((reaction_counts ->> sqlc.arg('emoji')::text)::integer, 0) + sqlc.arg('delta')::integer <= 0
+    THEN reaction_counts - sqlc.arg('emoji')::text
+  ELSE jsonb_set(
+    reaction_counts,
+    ARRAY[sqlc.arg('emoji')::text],
+    to_jsonb(COALESCE((reaction_counts ->> sqlc.arg('emoji')::text)::integer, 0) + sqlc.arg('delta')::integer)
+  )
+END
+WHERE id = sqlc.arg('id');
+
+-- name: ListChirpsByHashtag :many
+SELECT *
+FROM chirps
+WHERE deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND EXISTS (
+  SELECT 1
+  FROM chirp_entities
+  WHERE chirp_entities.chirp_id = chirps.id
+  AND chirp_entities.kind = 'hashtag'
+  AND lower(chirp_entities.value) = lower(sqlc.arg('tag')::text)
+)
+AND visibility <> 'unlisted'
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg('viewer_id')::uuid)
+AND NOT chirp_hidden_from(user_id, sqlc.arg('viewer_id')::uuid)
+AND (
+  sqlc.narg('before_created_at')::timestamp IS NULL
+  OR (created_at, id) < (sqlc.narg('before_created_at'), sqlc.narg('before_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListChirpsMentioningUser :many
+SELECT *
+FROM chirps
+WHERE deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND EXISTS (
+  SELECT 1
+  FROM chirp_entities
+  WHERE chirp_entities.chirp_id = chirps.id
+  AND chirp_entities.mentioned_user_id = sqlc.arg('user_id')::uuid
+)
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg('viewer_id')::uuid)
+AND NOT chirp_hidden_from(user_id, sqlc.arg('viewer_id')::uuid)
+AND (
+  sqlc.narg('before_created_at')::timestamp IS NULL
+  OR (created_at, id) < (sqlc.narg('before_created_at'), sqlc.narg('before_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListExpiredChirpIDs :many
+SELECT id
+FROM chirps
+WHERE expires_at <= NOW()
+ORDER BY expires_at ASC
+LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: TombstoneChirpsWithReplies :execrows
+UPDATE chirps
+SET body = '', content_warning = NULL, expires_at = NULL, deleted_at = NOW(), updated_at = NOW()
+WHERE id = ANY(sqlc.arg('ids')::uuid[])
+AND EXISTS (
+  SELECT 1
+  FROM chirps AS replies
+  WHERE replies.in_reply_to_id = chirps.id
+);
+
+-- name: PurgeChirps :execrows
+DELETE FROM chirps
+WHERE id = ANY(sqlc.arg('ids')::uuid[])
+AND NOT EXISTS (
+  SELECT 1
+  FROM chirps AS replies
+  WHERE replies.in_reply_to_id = chirps.id
+);
+
+-- name: ListStreamChirpsByIDs :many
+SELECT *
+FROM chirps
+WHERE id = ANY(sqlc.arg('ids')::uuid[])
+AND deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+AND (
+  sqlc.narg('tag')::text IS NULL
+  OR EXISTS (
+    SELECT 1
+    FROM chirp_entities
+    WHERE chirp_entities.chirp_id = chirps.id
+    AND chirp_entities.kind = 'hashtag'
+    AND lower(chirp_entities.value) = lower(sqlc.narg('tag'))
+  )
+)
+AND ((sqlc.narg('author_id')::uuid IS NOT NULL AND sqlc.narg('tag')::text IS NULL) OR visibility <> 'unlisted')
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg('viewer_id')::uuid)
+AND NOT chirp_hidden_from(user_id, sqlc.arg('viewer_id')::uuid)
+ORDER BY created_at ASC, id ASC;
+
+-- name: ListStreamChirpsAfter :many
+SELECT *
+FROM chirps
+WHERE (created_at, id) > (sqlc.arg('after_created_at')::timestamp, sqlc.arg('after_id')::uuid)
+AND deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+AND (
+  sqlc.narg('tag')::text IS NULL
+  OR EXISTS (
+    SELECT 1
+    FROM chirp_entities
+    WHERE chirp_entities.chirp_id = chirps.id
+    AND chirp_entities.kind = 'hashtag'
+    AND lower(chirp_entities.value) = lower(sqlc.narg('tag'))
+  )
+)
+AND ((sqlc.narg('author_id')::uuid IS NOT NULL AND sqlc.narg('tag')::text IS NULL) OR visibility <> 'unlisted')
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg('viewer_id')::uuid)
+AND NOT chirp_hidden_from(user_id, sqlc.arg('viewer_id')::uuid)
+ORDER BY created_at ASC, id ASC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,120 @@
+-- name: CreateConversation :one
+INSERT INTO conversations (
+  id,
+  is_group,
+  title,
+  filter_profanity,
+  created_by,
+  created_at,
+  updated_at
+) VALUES (
+  gen_random_uuid(),
+  $1,
+  $2,
+  $3,
+  $4,
+  NOW(),
+  NOW()
+)
+RETURNING *;
+
+-- name: AddConversationMember :exec
+INSERT INTO conversation_members (
+  conversation_id,
+  user_id,
+  joined_at,
+  last_read_at
+) VALUES (
+  $1,
+  $2,
+  NOW(),
+  NOW()
+)
+ON CONFLICT DO NOTHING;
+
+-- name: LockDirectConversation :exec
+SELECT pg_advisory_xact_lock(
+  hashtext('direct_conversations'),
+  hashtext(
+    least(sqlc.arg('user_id')::uuid, sqlc.arg('other_user_id')::uuid)::text
+    || ':' ||
+    greatest(sqlc.arg('user_id')::uuid, sqlc.arg('other_user_id')::uuid)::text
+  )
+);
+
+-- name: FindDirectConversation :one
+SELECT *
+FROM conversations
+WHERE NOT is_group
+AND EXISTS (
+  SELECT 1
+  FROM conversation_members
+  WHERE conversation_members.conversation_id = conversations.id
+  AND conversation_members.user_id = sqlc.arg('user_id')::uuid
+)
+AND EXISTS (
+  SELECT 1
+  FROM conversation_members
+  WHERE conversation_members.conversation_id = conversations.id
+  AND conversation_members.user_id = sqlc.arg('other_user_id')::uuid
+)
+ORDER BY created_at ASC
+LIMIT 1;
+
+-- name: GetConversation :one
+SELECT conversations.*
+FROM conversations
+JOIN conversation_members ON conversation_members.conversation_id = conversations.id
+WHERE conversations.id = $1
+AND conversation_members.user_id = $2;
+
+-- name: ListConversations :many
+SELECT conversations.*
+FROM conversations
+JOIN conversation_members ON conversation_members.conversation_id = conversations.id
+WHERE conversation_members.user_id = sqlc.arg('user_id')
+AND (
+  sqlc.narg('before_updated_at')::timestamp IS NULL
+  OR (conversations.updated_at, conversations.id) < (sqlc.narg('before_updated_at'), sqlc.narg('before_id')::uuid)
+)
+ORDER BY conversations.updated_at DESC, conversations.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListConversationMembers :many
+SELECT conversation_id, user_id
+FROM conversation_members
+WHERE conversation_id = ANY(sqlc.arg('conversation_ids')::uuid[])
+ORDER BY joined_at ASC, user_id ASC;
+
+-- name: ListUnreadCounts :many
+SELECT conversation_members.conversation_id, count(messages.id) AS unread_count
+FROM conversation_members
+JOIN messages ON messages.conversation_id = conversation_members.conversation_id
+WHERE conversation_members.user_id = sqlc.arg('user_id')
+AND conversation_members.conversation_id = ANY(sqlc.arg('conversation_ids')::uuid[])
+AND messages.created_at > conversation_members.last_read_at
+AND messages.sender_id IS DISTINCT FROM conversation_members.user_id
+GROUP BY conversation_members.conversation_id;
+
+-- name: TouchConversation :exec
+UPDATE conversations
+SET updated_at = NOW()
+WHERE id = $1;
+
+-- name: MarkConversationRead :exec
+UPDATE conversation_members
+SET last_read_at = NOW()
+WHERE conversation_id = $1 AND user_id = $2;
+
+-- name: LeaveConversation :execrows
+DELETE FROM conversation_members
+WHERE conversation_id = $1 AND user_id = $2;
+
+-- name: DeleteEmptyConversation :exec
+DELETE FROM conversations
+WHERE id = $1
+AND NOT EXISTS (
+  SELECT 1
+  FROM conversation_members
+  WHERE conversation_members.conversation_id = conversations.id
+);
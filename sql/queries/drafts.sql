@@ -0,0 +1,91 @@
+-- name: CreateDraft :one
+INSERT INTO drafts (
+  id,
+  user_id,
+  body,
+  in_reply_to_id,
+  quote_of_id,
+  media_ids,
+  publish_at,
+  status,
+  visibility,
+  content_warning,
+  expires_in_seconds,
+  created_at,
+  updated_at
+) VALUES (
+  gen_random_uuid(),
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  $8,
+  $9,
+  $10,
+  NOW(),
+  NOW()
+)
+RETURNING *;
+
+-- name: GetDraft :one
+SELECT *
+FROM drafts
+WHERE id = $1 AND user_id = $2;
+
+-- name: ListDrafts :many
+SELECT *
+FROM drafts
+WHERE user_id = sqlc.arg('user_id')
+AND (
+  sqlc.narg('before_created_at')::timestamp IS NULL
+  OR (created_at, id) < (sqlc.narg('before_created_at'), sqlc.narg('before_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: UpdateDraft :one
+UPDATE drafts
+SET body = $3,
+    in_reply_to_id = $4,
+    quote_of_id = $5,
+    media_ids = $6,
+    publish_at = $7,
+    status = $8,
+    visibility = $9,
+    content_warning = $10,
+    expires_in_seconds = $11,
+    error = NULL,
+    updated_at = NOW()
+WHERE id = $1 AND user_id = $2 AND status <> 'published'
+RETURNING *;
+
+-- name: DeleteDraft :execrows
+DELETE FROM drafts
+WHERE id = $1 AND user_id = $2;
+
+-- name: ClaimDueDraft :one
+SELECT *
+FROM drafts
+WHERE status = 'scheduled' AND publish_at <= NOW()
+ORDER BY publish_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkDraftPublished :exec
+UPDATE drafts
+SET status = 'published',
+    chirp_id = sqlc.arg('chirp_id')::uuid,
+    error = NULL,
+    published_at = NOW(),
+    updated_at = NOW()
+WHERE id = sqlc.arg('id');
+
+-- name: MarkDraftFailed :exec
+UPDATE drafts
+SET status = 'failed',
+    error = sqlc.arg('error')::text,
+    updated_at = NOW()
+WHERE id = sqlc.arg('id');
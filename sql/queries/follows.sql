@@ -0,0 +1,39 @@
+-- name: FollowUser :execrows
+INSERT INTO follows (
+  follower_id,
+  followee_id,
+  created_at
+) VALUES (
+  $1,
+  $2,
+  NOW()
+)
+ON CONFLICT DO NOTHING;
+
+-- name: UnfollowUser :execrows
+DELETE FROM follows
+WHERE follower_id = $1 AND followee_id = $2;
+
+-- name: ListFollowers :many
+SELECT users.id, users.created_at, users.updated_at, users.is_chirpy_red, users.handle, follows.created_at AS followed_at
+FROM follows
+JOIN users ON users.id = follows.follower_id
+WHERE follows.followee_id = sqlc.arg('user_id')
+AND (
+  sqlc.narg('before_followed_at')::timestamp IS NULL
+  OR (follows.created_at, users.id) < (sqlc.narg('before_followed_at'), sqlc.narg('before_id')::uuid)
+)
+ORDER BY follows.created_at DESC, users.id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListFollowing :many
+SELECT users.id, users.created_at, users.updated_at, users.is_chirpy_red, users.handle, follows.created_at AS followed_at
+FROM follows
+JOIN users ON users.id = follows.followee_id
+WHERE follows.follower_id = sqlc.arg('user_id')
+AND (
+  sqlc.narg('before_followed_at')::timestamp IS NULL
+  OR (follows.created_at, users.id) < (sqlc.narg('before_followed_at'), sqlc.narg('before_id')::uuid)
+)
+ORDER BY follows.created_at DESC, users.id DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,34 @@
+-- name: LockJWTKeys :exec
+SELECT pg_advisory_xact_lock(hashtext('jwt_keys'));
+
+-- name: CreateJWTKey :one
+INSERT INTO jwt_keys (
+  id,
+  algorithm,
+  private_key,
+  created_at,
+  activates_at
+) VALUES (
+  $1,
+  $2,
+  $3,
+  NOW(),
+  $4
+)
+RETURNING *;
+
+-- name: RetireJWTKeys :exec
+UPDATE jwt_keys
+SET retires_at = sqlc.arg('retires_at')::timestamp
+WHERE retires_at IS NULL
+AND id <> sqlc.arg('except_id')::text;
+
+-- name: ListJWTKeys :many
+SELECT *
+FROM jwt_keys
+WHERE retires_at IS NULL OR retires_at > NOW()
+ORDER BY activates_at ASC, id ASC;
+
+-- name: DeleteRetiredJWTKeys :exec
+DELETE FROM jwt_keys
+WHERE retires_at <= NOW();
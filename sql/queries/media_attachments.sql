@@ -0,0 +1,59 @@
+-- name: AttachMedia :execrows
+UPDATE media_attachments
+SET chirp_id = sqlc.arg('chirp_id')::uuid,
+    position = array_position(sqlc.arg('media_ids')::uuid[], id)
+WHERE id = ANY(sqlc.arg('media_ids')::uuid[])
+  AND user_id = sqlc.arg('user_id')
+  AND chirp_id IS NULL;
+
+-- name: CreateMediaAttachment :one
+INSERT INTO media_attachments (
+  id,
+  user_id,
+  content_type,
+  width,
+  height,
+  size_bytes,
+  storage_key,
+  thumbnail_key,
+  created_at
+) VALUES (
+  gen_random_uuid(),
+  $1,
+  $2,
+  $3,
+  $4,
+  $5,
+  $6,
+  $7,
+  NOW()
+)
+RETURNING *;
+
+-- name: DeleteChirpMedia :many
+DELETE FROM media_attachments
+WHERE chirp_id = sqlc.arg('chirp_id')::uuid
+RETURNING storage_key, thumbnail_key;
+
+-- name: DeleteStaleMedia :many
+DELETE FROM media_attachments
+WHERE chirp_id IS NULL
+  AND created_at < $1
+  AND NOT EXISTS (
+    SELECT 1
+    FROM drafts
+    WHERE drafts.status <> 'published'
+    AND media_attachments.id = ANY(drafts.media_ids)
+  )
+RETURNING storage_key, thumbnail_key;
+
+-- name: ListChirpMedia :many
+SELECT *
+FROM media_attachments
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+ORDER BY chirp_id, position;
+
+-- name: GetMediaAttachmentByKey :one
+SELECT *
+FROM media_attachments
+WHERE storage_key = sqlc.arg('key')::text OR thumbnail_key = sqlc.arg('key')::text;
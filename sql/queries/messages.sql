@@ -0,0 +1,36 @@
+-- name: CreateMessage :one
+INSERT INTO messages (
+  id,
+  conversation_id,
+  sender_id,
+  body,
+  created_at
+) VALUES (
+  gen_random_uuid(),
+  $1,
+  $2,
+  $3,
+  NOW()
+)
+RETURNING *;
+
+-- name: ListMessages :many
+SELECT *
+FROM messages
+WHERE conversation_id = sqlc.arg('conversation_id')
+AND (
+  sqlc.narg('before_created_at')::timestamp IS NULL
+  OR (created_at, id) < (sqlc.narg('before_created_at'), sqlc.narg('before_id')::uuid)
+)
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListLatestMessages :many
+SELECT *
+FROM messages
+WHERE id IN (
+  SELECT DISTINCT ON (latest.conversation_id) latest.id
+  FROM messages AS latest
+  WHERE latest.conversation_id = ANY(sqlc.arg('conversation_ids')::uuid[])
+  ORDER BY latest.conversation_id, latest.created_at DESC, latest.id DESC
+);
@@ -0,0 +1,40 @@
+-- name: CreateMutedKeyword :one
+INSERT INTO muted_keywords (
+  id,
+  user_id,
+  phrase,
+  whole_word,
+  expires_at,
+  created_at
+) VALUES (
+  gen_random_uuid(),
+  $1,
+  $2,
+  $3,
+  $4,
+  NOW()
+)
+ON CONFLICT (user_id, phrase) DO UPDATE
+SET whole_word = EXCLUDED.whole_word, expires_at = EXCLUDED.expires_at
+RETURNING *;
+
+-- name: ListMutedKeywords :many
+SELECT *
+FROM muted_keywords
+WHERE user_id = $1
+AND (expires_at IS NULL OR expires_at > NOW())
+ORDER BY created_at ASC, id ASC;
+
+-- name: CountMutedKeywords :one
+SELECT count(*)
+FROM muted_keywords
+WHERE user_id = $1
+AND (expires_at IS NULL OR expires_at > NOW());
+
+-- name: DeleteMutedKeyword :execrows
+DELETE FROM muted_keywords
+WHERE id = $1 AND user_id = $2;
+
+-- name: DeleteExpiredMutedKeywords :exec
+DELETE FROM muted_keywords
+WHERE user_id = $1 AND expires_at <= NOW();
@@ -0,0 +1,87 @@
+-- name: UpsertNotification :one
+INSERT INTO notifications (
+  id,
+  user_id,
+  kind,
+  chirp_id,
+  group_key,
+  created_at,
+  updated_at
+) VALUES (
+  gen_random_uuid(),
+  $1,
+  $2,
+  $3,
+  $4,
+  NOW(),
+  NOW()
+)
+ON CONFLICT (user_id, group_key) WHERE read_at IS NULL DO UPDATE
+SET group_key = EXCLUDED.group_key
+RETURNING *;
+
+-- name: AddNotificationActor :execrows
+INSERT INTO notification_actors (
+  notification_id,
+  actor_id,
+  created_at
+) VALUES (
+  $1,
+  $2,
+  NOW()
+)
+ON CONFLICT DO NOTHING;
+
+-- name: TouchNotification :exec
+UPDATE notifications
+SET updated_at = NOW()
+WHERE id = $1;
+
+-- name: ListNotifications :many
+SELECT *
+FROM notifications
+WHERE user_id = sqlc.arg('user_id')
+AND (
+  sqlc.narg('before_updated_at')::timestamp IS NULL
+  OR (updated_at, id) < (sqlc.narg('before_updated_at'), sqlc.narg('before_id')::uuid)
+)
+ORDER BY updated_at DESC, id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListRecentNotificationActors :many
+SELECT notification_id, actor_id
+FROM (
+  SELECT
+    notification_id,
+    actor_id,
+    created_at,
+    row_number() OVER (PARTITION BY notification_id ORDER BY created_at DESC, actor_id) AS position
+  FROM notification_actors
+  WHERE notification_id = ANY(sqlc.arg('notification_ids')::uuid[])
+) ranked
+WHERE position <= sqlc.arg('per_notification')::int
+ORDER BY notification_id, created_at DESC, actor_id;
+
+-- name: CountNotificationActors :many
+SELECT notification_id, count(*) AS actor_count
+FROM notification_actors
+WHERE notification_id = ANY(sqlc.arg('notification_ids')::uuid[])
+GROUP BY notification_id;
+
+-- name: CountUnreadNotifications :one
+SELECT count(*)
+FROM notifications
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1 AND user_id = $2;
+
+-- name: MarkAllNotificationsRead :exec
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1 AND read_at IS NULL;
+
+-- name: IsHiddenFrom :one
+SELECT chirp_hidden_from(sqlc.arg('author_id')::uuid, sqlc.arg('viewer_id')::uuid)::boolean AS hidden;
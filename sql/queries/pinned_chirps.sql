@@ -0,0 +1,36 @@
+-- name: PinChirp :execrows
+INSERT INTO pinned_chirps (
+  user_id,
+  chirp_id,
+  created_at
+) VALUES (
+  $1,
+  $2,
+  NOW()
+)
+ON CONFLICT DO NOTHING;
+
+-- name: UnpinChirp :exec
+DELETE FROM pinned_chirps
+WHERE user_id = $1 AND chirp_id = $2;
+
+-- name: CountPinnedChirps :one
+SELECT count(*)
+FROM pinned_chirps
+WHERE user_id = $1;
+
+-- name: ListPinnedChirps :many
+SELECT chirps.*
+FROM chirps
+JOIN pinned_chirps ON pinned_chirps.chirp_id = chirps.id
+WHERE pinned_chirps.user_id = sqlc.arg('user_id')
+AND chirps.deleted_at IS NULL
+AND (chirps.expires_at IS NULL OR chirps.expires_at > NOW())
+AND chirp_visible_to(chirps.id, chirps.user_id, chirps.visibility, sqlc.arg('viewer_id')::uuid)
+AND NOT chirp_hidden_from(chirps.user_id, sqlc.arg('viewer_id')::uuid)
+ORDER BY pinned_chirps.created_at DESC;
+
+-- name: ListPinnedChirpIDs :many
+SELECT chirp_id
+FROM pinned_chirps
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
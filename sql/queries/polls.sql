@@ -0,0 +1,78 @@
+-- name: CreatePoll :exec
+INSERT INTO polls (
+  chirp_id,
+  closes_at,
+  created_at
+) VALUES (
+  $1,
+  $2,
+  NOW()
+);
+
+-- name: CreatePollOption :exec
+INSERT INTO poll_options (
+  chirp_id,
+  position,
+  label
+) VALUES (
+  $1,
+  $2,
+  $3
+);
+
+-- name: GetPoll :one
+SELECT *
+FROM polls
+WHERE chirp_id = $1;
+
+-- name: ListPolls :many
+SELECT *
+FROM polls
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
+
+-- name: ListPollOptions :many
+SELECT *
+FROM poll_options
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[])
+ORDER BY chirp_id, position;
+
+-- name: CreatePollVote :execrows
+INSERT INTO poll_votes (
+  chirp_id,
+  user_id,
+  position,
+  created_at
+) VALUES (
+  $1,
+  $2,
+  $3,
+  NOW()
+)
+ON CONFLICT DO NOTHING;
+
+-- name: IncrementPollOptionVotes :exec
+UPDATE poll_options
+SET vote_count = vote_count + 1
+WHERE chirp_id = $1 AND position = $2;
+
+-- name: ListPollVotesByUser :many
+SELECT chirp_id, position
+FROM poll_votes
+WHERE user_id = sqlc.arg('user_id')
+AND chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
+
+-- name: FinalizeExpiredPolls :many
+UPDATE polls
+SET finalized_at = NOW()
+WHERE finalized_at IS NULL AND closes_at <= NOW()
+RETURNING chirp_id;
+
+-- name: RecountPollVotes :exec
+UPDATE poll_options
+SET vote_count = (
+  SELECT count(*)
+  FROM poll_votes
+  WHERE poll_votes.chirp_id = poll_options.chirp_id
+  AND poll_votes.position = poll_options.position
+)
+WHERE chirp_id = ANY(sqlc.arg('chirp_ids')::uuid[]);
@@ -1,28 +1,67 @@
 -- name: CreateRefreshToken :one
 INSERT INTO refresh_tokens (
-  token,
+  lookup_id,
+  token_hash,
   created_at,
   updated_at,
   user_id,
-  expires_at
+  expires_at,
+  family_id,
+  session_started_at,
+  last_used_at,
+  user_agent,
+  ip
 ) VALUES (
   $1,
+  $2,
   NOW(),
   NOW(),
-  $2,
-  $3
+  $3,
+  $4,
+  $5,
+  $6,
+  NOW(),
+  $7,
+  $8
 )
 RETURNING *;
 
--- name: GetUserFromRefreshToken :one
-SELECT users.*
-FROM users
-JOIN refresh_tokens ON users.ID = refresh_tokens.user_id
-WHERE refresh_tokens.token = $1 
-AND refresh_tokens.revoked_at IS NULL 
-AND refresh_tokens.expires_at > NOW();
+-- name: GetRefreshTokenForUpdate :one
+SELECT *
+FROM refresh_tokens
+WHERE lookup_id = $1 AND token_hash = $2
+FOR UPDATE;
+
+-- name: RotateRefreshToken :exec
+UPDATE refresh_tokens
+SET rotated_at = sqlc.arg('rotated_at')::timestamp, updated_at = NOW()
+WHERE lookup_id = sqlc.arg('lookup_id');
 
 -- name: RevokeRefreshToken :exec
 UPDATE refresh_tokens
 SET revoked_at = NOW(), updated_at = NOW()
-WHERE token = $1;
+WHERE lookup_id = $1 AND token_hash = $2;
+
+-- name: RevokeRefreshTokenFamily :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE family_id = $1 AND revoked_at IS NULL;
+
+-- name: ListSessions :many
+SELECT *
+FROM refresh_tokens
+WHERE user_id = $1
+AND revoked_at IS NULL
+AND rotated_at IS NULL
+AND expires_at > NOW()
+ORDER BY last_used_at DESC, family_id;
+
+-- name: RevokeSession :execrows
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE family_id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: RevokeAllSessions :exec
+UPDATE refresh_tokens
+SET revoked_at = NOW(), updated_at = NOW()
+WHERE user_id = $1 AND revoked_at IS NULL;
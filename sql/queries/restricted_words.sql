@@ -0,0 +1,20 @@
+-- name: CreateRestrictedWord :one
+INSERT INTO restricted_words (
+  id,
+  pattern,
+  created_at
+) VALUES (
+  gen_random_uuid(),
+  $1,
+  NOW()
+)
+RETURNING *;
+
+-- name: DeleteRestrictedWord :execrows
+DELETE FROM restricted_words
+WHERE id = $1;
+
+-- name: ListRestrictedWords :many
+SELECT *
+FROM restricted_words
+ORDER BY pattern ASC;
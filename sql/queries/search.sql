@@ -0,0 +1,36 @@
+-- name: SearchChirps :many
+SELECT *
+FROM chirps
+WHERE deleted_at IS NULL
+AND (expires_at IS NULL OR expires_at > NOW())
+AND (
+  sqlc.arg('query')::text = ''
+  OR to_tsvector('english', body) @@ websearch_to_tsquery('english', sqlc.arg('query')::text)
+)
+AND (sqlc.narg('author_id')::uuid IS NULL OR user_id = sqlc.narg('author_id'))
+AND (sqlc.narg('author_id')::uuid IS NOT NULL OR visibility <> 'unlisted')
+AND chirp_visible_to(id, user_id, visibility, sqlc.arg('viewer_id')::uuid)
+AND NOT chirp_hidden_from(user_id, sqlc.arg('viewer_id')::uuid)
+AND (
+  sqlc.narg('hashtag')::text IS NULL
+  OR EXISTS (
+    SELECT 1
+    FROM chirp_entities
+    WHERE chirp_entities.chirp_id = chirps.id
+    AND chirp_entities.kind = 'hashtag'
+    AND lower(chirp_entities.value) = lower(sqlc.narg('hashtag'))
+  )
+)
+ORDER BY
+  ts_rank(to_tsvector('english', body), websearch_to_tsquery('english', sqlc.arg('query')::text)) DESC,
+  created_at DESC,
+  id DESC
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
+
+-- name: SearchUsers :many
+SELECT *
+FROM users
+WHERE lower(handle) LIKE sqlc.arg('prefix')::text || '%'
+ORDER BY lower(handle) = sqlc.arg('term')::text DESC, lower(handle) ASC
+LIMIT sqlc.arg('limit');
@@ -36,3 +36,30 @@ UPDATE users
 SET is_chirpy_red = TRUE, updated_at = NOW()
 WHERE id = $1
 RETURNING *;
+
+-- name: GetUserByID :one
+SELECT *
+FROM users
+WHERE id = $1;
+
+-- name: UpdateUserHandle :one
+UPDATE users
+SET handle = $2, updated_at = NOW()
+WHERE id = $1
+RETURNING *;
+
+-- name: ListUsersByHandles :many
+SELECT *
+FROM users
+WHERE lower(handle) = ANY(sqlc.arg('handles')::text[]);
+
+-- name: GetUserByHandle :one
+SELECT *
+FROM users
+WHERE lower(handle) = lower(sqlc.arg('handle')::text);
+
+-- name: LockUser :exec
+SELECT id
+FROM users
+WHERE id = $1
+FOR UPDATE;
@@ -0,0 +1,9 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS chirps_created_at_id_idx
+  ON chirps (created_at, id);
+CREATE INDEX IF NOT EXISTS chirps_user_id_created_at_id_idx
+  ON chirps (user_id, created_at, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS chirps_user_id_created_at_id_idx;
+DROP INDEX IF EXISTS chirps_created_at_id_idx;
@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE chirps
+  ADD COLUMN in_reply_to_id UUID REFERENCES chirps(id) ON DELETE SET NULL,
+  ADD COLUMN root_id UUID REFERENCES chirps(id) ON DELETE SET NULL,
+  ADD COLUMN deleted_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS chirps_in_reply_to_id_idx
+  ON chirps (in_reply_to_id);
+CREATE INDEX IF NOT EXISTS chirps_root_id_idx
+  ON chirps (root_id);
+
+-- +goose Down
+ALTER TABLE chirps
+  DROP COLUMN deleted_at,
+  DROP COLUMN root_id,
+  DROP COLUMN in_reply_to_id;
@@ -0,0 +1,30 @@
+-- +goose Up
+ALTER TABLE chirps
+  ADD COLUMN like_count INTEGER NOT NULL DEFAULT 0,
+  ADD COLUMN reaction_counts JSONB NOT NULL DEFAULT '{}';
+
+CREATE TABLE IF NOT EXISTS chirp_likes (
+  chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (chirp_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS chirp_likes_user_id_idx
+  ON chirp_likes (user_id);
+
+CREATE TABLE IF NOT EXISTS chirp_reactions (
+  chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  emoji TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (chirp_id, user_id, emoji)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS chirp_reactions;
+DROP TABLE IF EXISTS chirp_likes;
+
+ALTER TABLE chirps
+  DROP COLUMN reaction_counts,
+  DROP COLUMN like_count;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS chirp_revisions (
+  id UUID PRIMARY KEY,
+  chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+  body TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  replaced_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS chirp_revisions_chirp_id_replaced_at_idx
+  ON chirp_revisions (chirp_id, replaced_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS chirp_revisions;
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE users
+  ADD COLUMN handle TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_lower_handle_idx
+  ON users (lower(handle));
+
+-- +goose Down
+ALTER TABLE users
+  DROP COLUMN handle;
@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS chirp_entities (
+  chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('hashtag', 'mention', 'url')),
+  value TEXT NOT NULL,
+  start_offset INTEGER NOT NULL,
+  end_offset INTEGER NOT NULL,
+  mentioned_user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+  PRIMARY KEY (chirp_id, start_offset)
+);
+
+CREATE INDEX IF NOT EXISTS chirp_entities_hashtag_idx
+  ON chirp_entities (lower(value))
+  WHERE kind = 'hashtag';
+CREATE INDEX IF NOT EXISTS chirp_entities_mentioned_user_id_idx
+  ON chirp_entities (mentioned_user_id)
+  WHERE mentioned_user_id IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS chirp_entities;
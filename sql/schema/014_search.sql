@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE INDEX IF NOT EXISTS chirps_body_search_idx
+  ON chirps USING GIN (to_tsvector('english', body));
+CREATE INDEX IF NOT EXISTS users_lower_email_pattern_idx
+  ON users (lower(email) text_pattern_ops);
+CREATE INDEX IF NOT EXISTS users_lower_handle_pattern_idx
+  ON users (lower(handle) text_pattern_ops);
+
+-- +goose Down
+DROP INDEX IF EXISTS users_lower_handle_pattern_idx;
+DROP INDEX IF EXISTS users_lower_email_pattern_idx;
+DROP INDEX IF EXISTS chirps_body_search_idx;
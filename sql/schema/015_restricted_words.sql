@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS restricted_words (
+  id UUID PRIMARY KEY,
+  pattern TEXT NOT NULL UNIQUE,
+  created_at TIMESTAMP NOT NULL
+);
+
+INSERT INTO restricted_words (id, pattern, created_at) VALUES
+  (gen_random_uuid(), 'kerfuffle', NOW()),
+  (gen_random_uuid(), 'sharbert', NOW()),
+  (gen_random_uuid(), 'fornax', NOW())
+ON CONFLICT (pattern) DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS restricted_words;
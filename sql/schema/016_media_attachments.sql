@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS media_attachments (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  chirp_id UUID REFERENCES chirps(id) ON DELETE CASCADE,
+  position INTEGER NOT NULL DEFAULT 0,
+  content_type TEXT NOT NULL,
+  width INTEGER NOT NULL,
+  height INTEGER NOT NULL,
+  size_bytes BIGINT NOT NULL,
+  storage_key TEXT NOT NULL UNIQUE,
+  thumbnail_key TEXT NOT NULL UNIQUE,
+  created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS media_attachments_chirp_id_idx
+  ON media_attachments (chirp_id, position)
+  WHERE chirp_id IS NOT NULL;
+
+-- +goose Down
+DROP TABLE IF EXISTS media_attachments;
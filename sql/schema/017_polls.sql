@@ -0,0 +1,33 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS polls (
+  chirp_id UUID PRIMARY KEY REFERENCES chirps(id) ON DELETE CASCADE,
+  closes_at TIMESTAMP NOT NULL,
+  finalized_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS polls_closes_at_idx
+  ON polls (closes_at)
+  WHERE finalized_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS poll_options (
+  chirp_id UUID NOT NULL REFERENCES polls(chirp_id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  label TEXT NOT NULL,
+  vote_count INTEGER NOT NULL DEFAULT 0,
+  PRIMARY KEY (chirp_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS poll_votes (
+  chirp_id UUID NOT NULL,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (chirp_id, user_id),
+  FOREIGN KEY (chirp_id, position) REFERENCES poll_options(chirp_id, position) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS poll_votes;
+DROP TABLE IF EXISTS poll_options;
+DROP TABLE IF EXISTS polls;
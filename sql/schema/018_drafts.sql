@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS drafts (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  body TEXT NOT NULL,
+  in_reply_to_id UUID,
+  quote_of_id UUID,
+  media_ids UUID[] NOT NULL DEFAULT '{}',
+  publish_at TIMESTAMP,
+  status TEXT NOT NULL CHECK (status IN ('draft', 'scheduled', 'published', 'failed')),
+  error TEXT,
+  chirp_id UUID REFERENCES chirps(id) ON DELETE SET NULL,
+  published_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS drafts_user_id_created_at_id_idx
+  ON drafts (user_id, created_at, id);
+CREATE INDEX IF NOT EXISTS drafts_due_idx
+  ON drafts (publish_at)
+  WHERE status = 'scheduled';
+
+-- +goose Down
+DROP TABLE IF EXISTS drafts;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS bookmarks (
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  chirp_id UUID NOT NULL REFERENCES chirps(id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (user_id, chirp_id)
+);
+
+CREATE INDEX IF NOT EXISTS bookmarks_user_id_created_at_chirp_id_idx
+  ON bookmarks (user_id, created_at, chirp_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS bookmarks;
@@ -0,0 +1,41 @@
+-- +goose Up
+ALTER TABLE chirps
+  ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public'
+    CHECK (visibility IN ('public', 'followers', 'mentioned', 'unlisted')),
+  ADD COLUMN content_warning TEXT;
+
+-- chirp_visible_to decides whether viewer may see a chirp. Anonymous viewers
+-- pass the nil UUID, which matches no user. Unlisted chirps are visible to
+-- anyone with a link; callers keep them out of public listings themselves.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirp_visible_to(chirp UUID, author UUID, chirp_visibility TEXT, viewer UUID)
+RETURNS BOOLEAN
+LANGUAGE sql
+STABLE
+AS $$
+  SELECT chirp_visibility IN ('public', 'unlisted')
+    OR viewer = author
+    OR EXISTS (
+      SELECT 1
+      FROM chirp_entities
+      WHERE chirp_entities.chirp_id = chirp
+      AND chirp_entities.mentioned_user_id = viewer
+    )
+    OR (
+      chirp_visibility = 'followers'
+      AND EXISTS (
+        SELECT 1
+        FROM follows
+        WHERE follows.follower_id = viewer
+        AND follows.followee_id = author
+      )
+    );
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION IF EXISTS chirp_visible_to(UUID, UUID, TEXT, UUID);
+
+ALTER TABLE chirps
+  DROP COLUMN IF EXISTS content_warning,
+  DROP COLUMN IF EXISTS visibility;
@@ -0,0 +1,10 @@
+-- +goose Up
+ALTER TABLE drafts
+  ADD COLUMN visibility TEXT NOT NULL DEFAULT 'public'
+    CHECK (visibility IN ('public', 'followers', 'mentioned', 'unlisted')),
+  ADD COLUMN content_warning TEXT;
+
+-- +goose Down
+ALTER TABLE drafts
+  DROP COLUMN IF EXISTS content_warning,
+  DROP COLUMN IF EXISTS visibility;
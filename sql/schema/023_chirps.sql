@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE chirps
+  ADD COLUMN expires_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS chirps_expires_at_idx
+  ON chirps (expires_at)
+  WHERE expires_at IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS chirps_expires_at_idx;
+
+ALTER TABLE chirps
+  DROP COLUMN IF EXISTS expires_at;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE drafts
+  ADD COLUMN expires_in_seconds INTEGER;
+
+-- +goose Down
+ALTER TABLE drafts
+  DROP COLUMN IF EXISTS expires_in_seconds;
@@ -0,0 +1,47 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS blocks (
+  blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (blocker_id, blocked_id),
+  CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS blocks_blocked_id_idx
+  ON blocks (blocked_id);
+
+CREATE TABLE IF NOT EXISTS mutes (
+  muter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  muted_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (muter_id, muted_id),
+  CHECK (muter_id <> muted_id)
+);
+
+-- chirp_hidden_from reports whether viewer has blocked or muted author, in
+-- which case author's chirps stay out of viewer's feeds and listings.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION chirp_hidden_from(author UUID, viewer UUID)
+RETURNS BOOLEAN
+LANGUAGE sql
+STABLE
+AS $$
+  SELECT EXISTS (
+    SELECT 1
+    FROM blocks
+    WHERE blocks.blocker_id = viewer
+    AND blocks.blocked_id = author
+  )
+  OR EXISTS (
+    SELECT 1
+    FROM mutes
+    WHERE mutes.muter_id = viewer
+    AND mutes.muted_id = author
+  );
+$$;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP FUNCTION IF EXISTS chirp_hidden_from(UUID, UUID);
+DROP TABLE IF EXISTS mutes;
+DROP TABLE IF EXISTS blocks;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS muted_keywords (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  phrase TEXT NOT NULL,
+  whole_word BOOLEAN NOT NULL DEFAULT TRUE,
+  expires_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL,
+  UNIQUE (user_id, phrase)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS muted_keywords;
@@ -0,0 +1,31 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS notifications (
+  id UUID PRIMARY KEY,
+  user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind TEXT NOT NULL CHECK (kind IN ('reply', 'mention', 'follow', 'like')),
+  chirp_id UUID REFERENCES chirps(id) ON DELETE CASCADE,
+  group_key TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  updated_at TIMESTAMP NOT NULL,
+  read_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_updated_at_idx
+  ON notifications (user_id, updated_at DESC, id DESC);
+
+-- While a notification is unread, new events with the same group key fold
+-- into it instead of creating another one.
+CREATE UNIQUE INDEX IF NOT EXISTS notifications_unread_group_idx
+  ON notifications (user_id, group_key)
+  WHERE read_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS notification_actors (
+  notification_id UUID NOT NULL REFERENCES notifications(id) ON DELETE CASCADE,
+  actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (notification_id, actor_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS notification_actors;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- notify_chirp_created tells every server instance LISTENing on
+-- chirp_created about a new chirp once its transaction commits. The payload
+-- is just the id; listeners look the chirp up themselves.
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION notify_chirp_created()
+RETURNS TRIGGER
+LANGUAGE plpgsql
+AS $$
+BEGIN
+  PERFORM pg_notify('chirp_created', NEW.id::text);
+  RETURN NEW;
+END;
+$$;
+-- +goose StatementEnd
+
+CREATE TRIGGER chirps_notify_created
+  AFTER INSERT ON chirps
+  FOR EACH ROW
+  EXECUTE FUNCTION notify_chirp_created();
+
+-- +goose Down
+DROP TRIGGER IF EXISTS chirps_notify_created ON chirps;
+DROP FUNCTION IF EXISTS notify_chirp_created();
@@ -0,0 +1,19 @@
+-- +goose Up
+-- Every login starts a family of refresh tokens; each refresh retires the
+-- token it was given and hands out the next one in the same family. Tokens
+-- issued before this migration start a family of their own.
+ALTER TABLE refresh_tokens
+  ADD COLUMN family_id UUID NOT NULL DEFAULT gen_random_uuid(),
+  ADD COLUMN rotated_at TIMESTAMP;
+
+ALTER TABLE refresh_tokens
+  ALTER COLUMN family_id DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_family_id_idx
+  ON refresh_tokens (family_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS refresh_tokens_family_id_idx;
+ALTER TABLE refresh_tokens
+  DROP COLUMN rotated_at,
+  DROP COLUMN family_id;
@@ -0,0 +1,27 @@
+-- +goose Up
+-- Refresh tokens are kept only as a SHA-256 hash, found by a lookup id made
+-- of their first 16 characters. Existing tokens are converted in place, so
+-- sessions survive the migration.
+ALTER TABLE refresh_tokens
+  ADD COLUMN lookup_id TEXT,
+  ADD COLUMN token_hash TEXT;
+
+UPDATE refresh_tokens
+SET lookup_id = left(token, 16),
+  token_hash = encode(sha256(convert_to(token, 'UTF8')), 'hex');
+
+ALTER TABLE refresh_tokens
+  DROP CONSTRAINT refresh_tokens_pkey,
+  DROP COLUMN token,
+  ALTER COLUMN lookup_id SET NOT NULL,
+  ALTER COLUMN token_hash SET NOT NULL,
+  ADD PRIMARY KEY (lookup_id);
+
+-- +goose Down
+-- The raw tokens are gone, so going back ends every session.
+DELETE FROM refresh_tokens;
+ALTER TABLE refresh_tokens
+  DROP CONSTRAINT refresh_tokens_pkey,
+  DROP COLUMN token_hash,
+  DROP COLUMN lookup_id,
+  ADD COLUMN token TEXT PRIMARY KEY;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- A session is a family of refresh tokens. Each token carries when its
+-- session started and the client it was last refreshed from, so the current
+-- token in a family describes the whole session.
+ALTER TABLE refresh_tokens
+  ADD COLUMN session_started_at TIMESTAMP,
+  ADD COLUMN last_used_at TIMESTAMP,
+  ADD COLUMN user_agent TEXT NOT NULL DEFAULT '',
+  ADD COLUMN ip TEXT NOT NULL DEFAULT '';
+
+UPDATE refresh_tokens
+SET session_started_at = created_at,
+  last_used_at = updated_at;
+
+ALTER TABLE refresh_tokens
+  ALTER COLUMN session_started_at SET NOT NULL,
+  ALTER COLUMN last_used_at SET NOT NULL,
+  ALTER COLUMN user_agent DROP DEFAULT,
+  ALTER COLUMN ip DROP DEFAULT;
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_id_idx
+  ON refresh_tokens (user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS refresh_tokens_user_id_idx;
+ALTER TABLE refresh_tokens
+  DROP COLUMN ip,
+  DROP COLUMN user_agent,
+  DROP COLUMN last_used_at,
+  DROP COLUMN session_started_at;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- jwt_keys is the keyring access tokens are signed with. The newest key
+-- whose activates_at has passed signs; a key keeps verifying until
+-- retires_at, which is set once a newer key takes over. Private keys are
+-- stored encrypted under the server's JWT_SECRET.
+CREATE TABLE IF NOT EXISTS jwt_keys (
+  id TEXT PRIMARY KEY,
+  algorithm TEXT NOT NULL CHECK (algorithm IN ('EdDSA', 'RS256')),
+  private_key TEXT NOT NULL,
+  created_at TIMESTAMP NOT NULL,
+  activates_at TIMESTAMP NOT NULL,
+  retires_at TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS jwt_keys;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- User search only matches handles now, so nothing reads this index.
+DROP INDEX IF EXISTS users_lower_email_pattern_idx;
+
+-- +goose Down
+CREATE INDEX IF NOT EXISTS users_lower_email_pattern_idx
+  ON users (lower(email) text_pattern_ops);
@@ -0,0 +1,22 @@
+-- +goose Up
+-- Phrases are stored lowercased from now on. Of the existing phrases that
+-- only differ in case, the newest one is kept.
+DELETE FROM muted_keywords
+WHERE id IN (
+  SELECT id
+  FROM (
+    SELECT id, row_number() OVER (
+      PARTITION BY user_id, lower(phrase)
+      ORDER BY created_at DESC, id DESC
+    ) AS rank
+    FROM muted_keywords
+  ) AS ranked
+  WHERE rank > 1
+);
+
+UPDATE muted_keywords
+SET phrase = lower(phrase)
+WHERE phrase <> lower(phrase);
+
+-- +goose Down
+-- The original case of the phrases isn't kept, so there's nothing to undo.
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Tanay-Verma/chirpy/internal/auth"
+	"github.com/google/uuid"
+)
+
+// viewerID identifies the caller on endpoints that also serve anonymous
+// requests. No Authorization header means uuid.Nil; a bad token is an error.
+func (cfg *apiConfig) viewerID(req *http.Request) (uuid.UUID, error) {
+	token, err := auth.GetBearerToken(req.Header)
+	if errors.Is(err, auth.ErrNoAuthHeaderIncluded) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return cfg.jwtKeys().ValidateJWT(token)
+}